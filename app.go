@@ -2,187 +2,269 @@ package main
 
 import (
 	"bytes"
+	"fmt"
 	"image"
-	"image/color"
-	"os/exec"
-	"runtime"
+	"log"
+	"os"
 	"time"
 
+	"github.com/aaronzipp/deeptube/config"
+	"github.com/aaronzipp/deeptube/player"
+	"github.com/aaronzipp/deeptube/refresh"
+	"github.com/aaronzipp/deeptube/thumbnail"
 	"github.com/aaronzipp/deeptube/video"
 	"github.com/aaronzipp/deeptube/youtube"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
 	"fyne.io/fyne/v2/canvas"
-	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/driver/desktop"
-	"fyne.io/fyne/v2/theme"
-	"fyne.io/fyne/v2/widget"
 )
 
-const numColumns = 4
-const numVideos = 20
 const logoPath = "assets/logo.png"
 
 const applicationName = "DeepTube"
 
-func openBrowser(url string) {
-	switch runtime.GOOS {
-	case "windows":
-		exec.Command("rundll32", "url.dll,FileProtocolHandler", url).Start()
-	case "darwin":
-		exec.Command("open", url).Start()
-	default:
-		exec.Command("xdg-open", url).Start()
-	}
+// applicationID identifies the app for its preferences and notifications.
+const applicationID = "io.github.aaronzipp.deeptube"
+
+var thumbnailQueue = thumbnail.NewQueue(4)
+var videoPlayer *player.Player
+
+// pendingThumbnails holds the card images that still show the placeholder,
+// keyed by video ID. It is only accessed from the Fyne goroutine.
+var pendingThumbnails = make(map[string][]*canvas.Image)
+
+// Thumbnails have a 16:9 ratio, like on YouTube, the ones of Shorts are
+// vertical. In the grid they grow with the cards.
+var (
+	gridThumbnailSize         = fyne.NewSize(224, 126)
+	compactThumbnailSize      = fyne.NewSize(128, 72)
+	shortsThumbnailSize       = fyne.NewSize(144, 256)
+	compactShortThumbnailSize = fyne.NewSize(54, 96)
+)
+
+func loadImage(data []byte, size fyne.Size) *canvas.Image {
+	thumbnail := canvas.NewImageFromFile(logoPath)
+	thumbnail.SetMinSize(size)
+	setThumbnail(thumbnail, data)
+	thumbnail.FillMode = canvas.ImageFillContain
+	return thumbnail
 }
 
-func loadImage(data []byte) *canvas.Image {
+// setThumbnail replaces the image shown by thumbnail,
+// keeping the current one if data can't be decoded.
+func setThumbnail(thumbnail *canvas.Image, data []byte) {
 	if len(data) == 0 {
-		return canvas.NewImageFromFile(logoPath)
+		return
 	}
 	img, _, err := image.Decode(bytes.NewReader(data))
 	if err != nil {
-		return canvas.NewImageFromFile(logoPath)
+		return
 	}
-	image := canvas.NewImageFromImage(img)
-	image.SetMinSize(fyne.NewSize(210, 118)) // 16:9 ratio, like YouTube
-	image.FillMode = canvas.ImageFillContain
-	return image
+	thumbnail.File = ""
+	thumbnail.Image = cropToRatio(img, thumbnail.MinSize())
 }
 
-func updateGrid(grid *fyne.Container, cards []fyne.CanvasObject) {
-	if grid.Objects == nil {
-		grid.Objects = cards
-	} else {
-		grid.Objects = append(grid.Objects, cards...)
+// cropToRatio cuts the sides off images wider than size. The thumbnails of
+// Shorts are landscape, with the vertical video between black bars.
+func cropToRatio(img image.Image, size fyne.Size) image.Image {
+	bounds := img.Bounds()
+	if size.Height == 0 {
+		return img
 	}
-	grid.Refresh()
+	width := int(float32(bounds.Dy())*size.Width/size.Height + 0.5)
+	sub, ok := img.(interface {
+		SubImage(image.Rectangle) image.Image
+	})
+	if width >= bounds.Dx() || !ok {
+		return img
+	}
+	x := bounds.Min.X + (bounds.Dx()-width)/2
+	return sub.SubImage(image.Rect(x, bounds.Min.Y, x+width, bounds.Max.Y))
 }
 
-func generateInitialCards(grid *fyne.Container, videos video.Videos) {
-	var cards []fyne.CanvasObject
-
-	for _, vid := range videos {
-		thumbnail := loadImage(vid.Thumbnail)
-
-		title := widget.NewLabelWithStyle(
-			vid.Title,
-			fyne.TextAlignLeading,
-			fyne.TextStyle{Bold: true},
-		)
-		title.Wrapping = fyne.TextWrapWord
-
-		channel := widget.NewLabelWithStyle(
-			vid.ChannelName,
-			fyne.TextAlignLeading,
-			fyne.TextStyle{Italic: true},
-		)
-		channel.Wrapping = fyne.TextWrapWord
-
-		durationText := canvas.NewText(
-			vid.VideoLength.String(),
-			theme.Color(theme.ColorNameForeground),
-		)
-		liveText := canvas.NewText("", theme.Color(theme.ColorNameForeground))
-		if vid.WasLive {
-			liveText.Text = " LIVE"
-			liveText.Color = color.RGBA{255, 0, 0, 255}
-			liveText.TextStyle = fyne.TextStyle{Bold: true}
-		}
-		dotText := canvas.NewText(" • ", theme.Color(theme.ColorNameForeground))
-		publishedText := canvas.NewText(
-			vid.TimeSincePublished(),
-			theme.Color(theme.ColorNameForeground),
-		)
-		publishedText.TextStyle = fyne.TextStyle{Italic: true}
-
-		bottomLine := container.NewHBox(durationText, liveText, dotText, publishedText)
-
-		infoBox := container.NewVBox(
-			title,
-			channel,
-			bottomLine,
-		)
-
-		card := container.NewVBox(
-			thumbnail,
-			infoBox,
-		)
-		var videoCard *fyne.Container
-
-		watchBtn := widget.NewButtonWithIcon("", theme.MediaPlayIcon(), func() {
-			openBrowser(vid.YouTubeLink())
-		})
+// refreshVideos fetches new videos, cleans up the ones past their retention
+// and queues the missing thumbnails.
+func refreshVideos(cfg config.Config) error {
+	started := time.Now()
+	_, err := youtube.RefreshVideos(cfg)
+	if err != nil {
+		return err
+	}
+	// Videos published since are notified about on the next refresh.
+	err = video.SetLastRefresh(started)
+	if err != nil {
+		return err
+	}
+	_, err = video.ApplyRetention(cfg.Retention)
+	if err != nil {
+		return err
+	}
+	_, err = thumbnail.Prune(cfg.Thumbnails)
+	if err != nil {
+		return err
+	}
+	return thumbnailQueue.EnqueueMissing(cfg.Thumbnails)
+}
 
-		hideBtn := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
-			go func() {
-				vid.Hide()
-			}()
-			grid.Remove(videoCard)
-			grid.Refresh()
-		})
+// mainFeed is the only window, it is created on the first launch and hidden
+// instead of closed.
+var mainFeed *feed
 
-		buttons := container.NewHBox(watchBtn, hideBtn)
-		content := container.NewBorder(nil, buttons, nil, nil, card)
-		videoCard = container.NewPadded(content)
-		cards = append(cards, videoCard)
+func launchGUI(a fyne.App, refresh, seen func()) {
+	if mainFeed == nil {
+		mainFeed = newFeed(a, *currentConfig.Load(), refresh, seen)
 	}
-
-	updateGrid(grid, cards)
+	mainFeed.visit()
+	mainFeed.window.Show()
+	mainFeed.window.RequestFocus()
 }
 
-func launchGUI(a fyne.App) {
-	videos, err := video.VideosFromDB(numVideos)
-	if err != nil {
-		panic(err)
+func main() {
+	// The config command reports the problems of the config itself, so it
+	// runs before the config has to be valid.
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		err := runConfig(os.Args[2:])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
 	}
 
-	w := a.NewWindow(applicationName)
-	w.SetIcon(a.Icon())
-
-	grid := container.NewGridWithColumns(numColumns)
-	generateInitialCards(grid, videos)
-
-	scroll := container.NewVScroll(grid)
+	cfg, err := config.Load(config.DefaultPath)
+	if err != nil {
+		log.Fatalf("failed loading %s: %s", config.DefaultPath, err)
+	}
 
-	w.SetContent(scroll)
-	w.Resize(fyne.NewSize(1200, 800))
-	w.Show()
-}
+	if len(os.Args) > 1 {
+		err := runCommand(cfg, os.Args[1:])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
 
-func main() {
-	a := app.New()
+	a := app.NewWithID(applicationID)
+	a.Settings().SetTheme(newTheme(a.Preferences()))
 
 	logo, err := fyne.LoadResourceFromPath(logoPath)
 	if err == nil {
 		a.SetIcon(logo)
 	}
 
-	launchItem := fyne.NewMenuItem("Launch", func() {
-		launchGUI(a)
+	thumbnailQueue.OnReady(func(videoID string, data []byte) {
+		fyne.Do(func() {
+			for _, thumbnail := range pendingThumbnails[videoID] {
+				setThumbnail(thumbnail, data)
+				thumbnail.Refresh()
+			}
+			delete(pendingThumbnails, videoID)
+		})
 	})
+	err = thumbnailQueue.Start()
+	if err != nil {
+		log.Fatalf("failed starting thumbnail downloads: %s", err)
+	}
+
+	videoPlayer = player.New(cfg.Player)
+	videoPlayer.OnProgress(func(videoID string, seconds int) {
+		fyne.Do(func() {
+			setProgress(videoID, seconds)
+		})
+	})
+
+	launchItem := fyne.NewMenuItem("Launch", nil)
+	refreshItem := fyne.NewMenuItem("Refresh", nil)
+	uploadsItem := fyne.NewMenuItem("New uploads", nil)
+	uploadsItem.ChildMenu = fyne.NewMenu("")
+	uploadsItem.Disabled = true
+	menu := fyne.NewMenu(applicationName, launchItem, refreshItem, uploadsItem)
+
+	// updateNewCount shows the number of videos since the last visit in the tray.
+	updateNewCount := func() {
+		launchItem.Label = "Launch"
+		lastSeen, err := video.LastSeen()
+		if err == nil {
+			count, err := video.CountNewSince(lastSeen, a.Preferences().Bool(prefHideShorts))
+			if err == nil && count > 0 {
+				launchItem.Label = fmt.Sprintf("Launch (%d new)", count)
+			}
+		}
+		menu.Refresh()
+	}
 
-	refreshItem := fyne.NewMenuItem("Refresh", func() {
-		err := youtube.RefreshVideos()
+	// uploads are the latest notified videos, newest first.
+	var uploads video.Videos
+
+	video.OnAdded(func(added video.Videos) {
+		// Read before the running refresh succeeds and moves it.
+		lastRefresh, err := video.LastRefresh()
 		if err != nil {
-			// TODO: handle this error by showing the user
+			log.Printf("failed loading the last refresh: %s", err)
 		}
+		fyne.Do(func() {
+			notified := notifyUploads(a, added, lastRefresh)
+			if len(notified) > 0 {
+				uploads = append(notified, uploads...)
+				uploads = uploads[:min(len(uploads), maxUploadItems)]
+				uploadsItem.ChildMenu.Items = uploadItems(uploads)
+				uploadsItem.Disabled = false
+			}
+			if mainFeed != nil && mainFeed.visible {
+				mainFeed.reload()
+			}
+		})
+	})
+
+	currentConfig.Store(&cfg)
+	scheduler := refresh.New(cfg.Refresh, func() error {
+		err := refreshVideos(*currentConfig.Load())
+		fyne.Do(updateNewCount)
+		return err
 	})
 
-	menu := fyne.NewMenu(applicationName, launchItem, refreshItem)
+	launchItem.Action = func() {
+		launchGUI(a, scheduler.Trigger, updateNewCount)
+	}
+	refreshItem.Action = scheduler.Trigger
+
+	// problemsItem leads the tray menu while the changed config is invalid.
+	items := menu.Items
+	var problems error
+	problemsItem := fyne.NewMenuItem("", func() {
+		launchItem.Action()
+		if problems != nil {
+			dialog.ShowError(problems, mainFeed.window)
+		}
+	})
+	showProblems := func(err error) {
+		fyne.Do(func() {
+			problems = err
+			menu.Items = items
+			if err != nil {
+				problemsItem.Label = fmt.Sprintf("Problems in %s…", cfg.Path)
+				menu.Items = append([]*fyne.MenuItem{problemsItem, fyne.NewMenuItemSeparator()}, items...)
+			}
+			menu.Refresh()
+		})
+	}
 
 	if desk, ok := a.(desktop.App); ok {
 		desk.SetSystemTrayMenu(menu)
 	}
 
-	go func() {
-		ticker := time.NewTicker(30 * time.Minute)
-		for range ticker.C {
-			// TODO: log any potential errors
-			youtube.RefreshVideos()
-		}
-	}()
+	scheduler.Start()
+
+	watcher, err := watchConfig(scheduler, showProblems)
+	if err != nil {
+		log.Printf("changes to %s apply after a restart, failed watching it: %s", cfg.Path, err)
+	} else {
+		defer watcher.Close()
+	}
 
 	a.Run()
 }
@@ -0,0 +1,178 @@
+// Package backup imports the watch history and watch later lists from the
+// backups of other YouTube clients.
+package backup
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/aaronzipp/deeptube/database"
+	"github.com/aaronzipp/deeptube/video"
+)
+
+// Item is a video from a backup. Zero times mean it wasn't watched or queued.
+type Item struct {
+	VideoID     string
+	Title       string
+	Channel     string
+	Duration    int
+	PublishedAt time.Time
+	WatchedAt   time.Time
+	// Position is the second the video was watched up to.
+	Position int
+	QueuedAt time.Time
+	// Short is set for videos linked as Shorts.
+	Short bool
+}
+
+// Result counts what an import changed.
+type Result struct {
+	// Added is the number of videos that weren't stored before.
+	Added int64
+	// Watched and Queued count the videos that weren't watched or queued before.
+	Watched int64
+	Queued  int64
+}
+
+// videoIDPattern matches the ID in YouTube watch and short links.
+var videoIDPattern = regexp.MustCompile(`(?:v=|youtu\.be/|shorts/)([\w-]{11})`)
+
+// isShortURL reports whether the link opens the video as a Short.
+func isShortURL(url string) bool {
+	return strings.Contains(url, "/shorts/")
+}
+
+func videoIDFromURL(url string) string {
+	match := videoIDPattern.FindStringSubmatch(url)
+	if match == nil {
+		return ""
+	}
+	return match[1]
+}
+
+// ImportFile imports a NewPipe backup zip or a FreeTube history.db or
+// playlists.db.
+func ImportFile(filename string) (Result, error) {
+	var items []Item
+	var err error
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".zip":
+		items, err = ReadNewPipe(filename)
+	case ".db", ".json", ".ndjson":
+		items, err = ReadFreeTube(filename)
+	default:
+		return Result{}, fmt.Errorf("unknown backup format %q, expected a NewPipe .zip or a FreeTube .db", filepath.Ext(filename))
+	}
+	if err != nil {
+		return Result{}, fmt.Errorf("failed reading %s: %w", filename, err)
+	}
+	return Apply(items)
+}
+
+// Apply stores the items in a single transaction. Videos that aren't known
+// yet get a placeholder row with what the backup knows about them, existing
+// watched and queued times are kept.
+func Apply(items []Item) (Result, error) {
+	ctx := context.Background()
+	db, err := database.Open()
+	if err != nil {
+		return Result{}, err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return Result{}, err
+	}
+	defer tx.Rollback()
+
+	queries := database.New(tx)
+
+	var result Result
+	for _, item := range items {
+		length := video.LengthFromSeconds(item.Duration)
+		// Without a publishing date the video is sorted in where it was seen.
+		published := item.PublishedAt
+		if published.IsZero() {
+			published = item.WatchedAt
+		}
+		if published.IsZero() {
+			published = item.QueuedAt
+		}
+		// Imported videos aren't new, they are fetched when they were watched
+		// or queued. Otherwise they'd be marked as new and their thumbnails downloaded.
+		fetched := item.WatchedAt
+		if fetched.IsZero() {
+			fetched = item.QueuedAt
+		}
+		if fetched.IsZero() {
+			fetched = published
+		}
+		videoType := video.NormalVideo
+		if item.Short {
+			videoType = video.ShortVideo
+		}
+		added, err := queries.AddPlaceholderVideo(ctx, database.AddPlaceholderVideoParams{
+			VideoID:      item.VideoID,
+			Title:        sql.NullString{String: item.Title, Valid: true},
+			ThumbnailUrl: sql.NullString{String: fmt.Sprintf(thumbnailURL, item.VideoID), Valid: true},
+			ChannelName:  sql.NullString{String: item.Channel, Valid: true},
+			PublishedAt:  nullTime(published),
+			Hours:        sql.NullInt64{Int64: int64(length.Hours), Valid: true},
+			Minutes:      sql.NullInt64{Int64: int64(length.Minutes), Valid: true},
+			Seconds:      sql.NullInt64{Int64: int64(length.Seconds), Valid: true},
+			FetchedAt:    nullTime(fetched),
+			VideoType:    string(videoType),
+		})
+		if err != nil {
+			return Result{}, err
+		}
+		result.Added += added
+
+		if !item.WatchedAt.IsZero() {
+			watched, err := queries.MarkVideoWatchedAt(ctx, database.MarkVideoWatchedAtParams{
+				WatchedAt: nullTime(item.WatchedAt),
+				VideoID:   item.VideoID,
+			})
+			if err != nil {
+				return Result{}, err
+			}
+			result.Watched += watched
+		}
+		if item.Position > 0 {
+			err = queries.SetVideoPosition(ctx, database.SetVideoPositionParams{
+				Position: sql.NullInt64{Int64: int64(item.Position), Valid: true},
+				VideoID:  item.VideoID,
+			})
+			if err != nil {
+				return Result{}, err
+			}
+		}
+		if !item.QueuedAt.IsZero() {
+			queued, err := queries.QueueVideoAt(ctx, database.QueueVideoAtParams{
+				QueuedAt: nullTime(item.QueuedAt),
+				VideoID:  item.VideoID,
+			})
+			if err != nil {
+				return Result{}, err
+			}
+			result.Queued += queued
+		}
+	}
+
+	return result, tx.Commit()
+}
+
+// thumbnailURL is the thumbnail YouTube has for every video.
+const thumbnailURL = "https://i.ytimg.com/vi/%s/hqdefault.jpg"
+
+func nullTime(t time.Time) sql.NullString {
+	if t.IsZero() {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: t.UTC().Format("2006-01-02 15:04:05"), Valid: true}
+}
@@ -0,0 +1,148 @@
+package backup
+
+import (
+	"archive/zip"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVideoIDFromURL(t *testing.T) {
+	testData := []struct {
+		input  string
+		output string
+	}{
+		{"https://www.youtube.com/watch?v=dQw4w9WgXcQ", "dQw4w9WgXcQ"},
+		{"https://youtu.be/dQw4w9WgXcQ", "dQw4w9WgXcQ"},
+		{"https://www.youtube.com/shorts/dQw4w9WgXcQ", "dQw4w9WgXcQ"},
+		{"https://soundcloud.com/artist/track", ""},
+	}
+
+	for _, tt := range testData {
+		t.Run(tt.input, func(t *testing.T) {
+			got := videoIDFromURL(tt.input)
+
+			if got != tt.output {
+				t.Errorf("Got %q, want %q", got, tt.output)
+			}
+		})
+	}
+}
+
+func TestParseFreeTube(t *testing.T) {
+	backup := `{"videoId":"aaaaaaaaaaa","title":"Watched","author":"Channel","lengthSeconds":600,"published":1700000000000,"timeWatched":1700000600000,"watchProgress":42.5,"_id":"1"}
+{"$$deleted":true,"_id":"2"}
+{"videoId":"","title":"Broken","_id":"4"}
+{"playlistName":"Favorites","videos":[{"videoId":"bbbbbbbbbbb"}],"_id":"3"}
+{"playlistName":"Watch Later","protected":true,"videos":[{"title":"Broken"},{"videoId":"ccccccccccc","title":"Later","author":"Channel","lengthSeconds":60,"timeAdded":1700001000000}],"_id":"watchLater"}
+`
+	got, err := parseFreeTube(strings.NewReader(backup))
+	if err != nil {
+		t.Fatalf("Got an unexpected error: %q", err)
+	}
+	want := []Item{
+		{
+			VideoID:     "aaaaaaaaaaa",
+			Title:       "Watched",
+			Channel:     "Channel",
+			Duration:    600,
+			PublishedAt: time.UnixMilli(1700000000000),
+			WatchedAt:   time.UnixMilli(1700000600000),
+			Position:    42,
+		},
+		{
+			VideoID:  "ccccccccccc",
+			Title:    "Later",
+			Channel:  "Channel",
+			Duration: 60,
+			QueuedAt: time.UnixMilli(1700001000000),
+		},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %+v, want %+v", got, want)
+	}
+}
+
+func TestReadNewPipe(t *testing.T) {
+	dir := t.TempDir()
+	dbFile := filepath.Join(dir, "newpipe.db")
+	db, err := sql.Open("sqlite", dbFile)
+	if err != nil {
+		t.Fatalf("Got an unexpected error: %q", err)
+	}
+	_, err = db.Exec(`
+create table streams (uid integer primary key, service_id integer, url text, title text, uploader text, duration integer, upload_date integer);
+create table stream_history (stream_id integer, access_date integer, repeat_count integer);
+create table stream_state (stream_id integer, progress_time integer);
+create table playlists (uid integer primary key, name text);
+create table playlist_stream_join (playlist_id integer, stream_id integer, join_index integer);
+insert into streams values (1, 0, 'https://www.youtube.com/watch?v=aaaaaaaaaaa', 'Watched', 'Channel', 600, 1700000000000);
+insert into streams values (2, 0, 'https://www.youtube.com/shorts/bbbbbbbbbbb', 'Later', 'Channel', 60, null);
+insert into streams values (3, 1, 'https://soundcloud.com/artist/track', 'Track', 'Artist', 200, null);
+insert into stream_history values (1, 1700000600000, 1), (1, 1700000300000, 1), (3, 1700000600000, 1);
+insert into stream_state values (1, 42000);
+insert into playlists values (1, 'Watch later');
+insert into playlist_stream_join values (1, 2, 0);
+`)
+	db.Close()
+	if err != nil {
+		t.Fatalf("Got an unexpected error: %q", err)
+	}
+
+	zipFile := filepath.Join(dir, "NewPipeData.zip")
+	writeZip(t, zipFile, dbFile)
+
+	got, err := ReadNewPipe(zipFile)
+	if err != nil {
+		t.Fatalf("Got an unexpected error: %q", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("Got %+v, want 2 items", got)
+	}
+	watched := Item{
+		VideoID:     "aaaaaaaaaaa",
+		Title:       "Watched",
+		Channel:     "Channel",
+		Duration:    600,
+		PublishedAt: time.UnixMilli(1700000000000),
+		WatchedAt:   time.UnixMilli(1700000600000),
+		Position:    42,
+	}
+	if !reflect.DeepEqual(got[0], watched) {
+		t.Errorf("Got %+v, want %+v", got[0], watched)
+	}
+	if got[1].VideoID != "bbbbbbbbbbb" || got[1].QueuedAt.IsZero() || !got[1].WatchedAt.IsZero() || !got[1].Short {
+		t.Errorf("Got %+v, want the Short bbbbbbbbbbb queued", got[1])
+	}
+}
+
+func writeZip(t *testing.T, zipFile, dbFile string) {
+	data, err := os.ReadFile(dbFile)
+	if err != nil {
+		t.Fatalf("Got an unexpected error: %q", err)
+	}
+	file, err := os.Create(zipFile)
+	if err != nil {
+		t.Fatalf("Got an unexpected error: %q", err)
+	}
+	defer file.Close()
+
+	archive := zip.NewWriter(file)
+	w, err := archive.Create("newpipe.db")
+	if err != nil {
+		t.Fatalf("Got an unexpected error: %q", err)
+	}
+	_, err = w.Write(data)
+	if err != nil {
+		t.Fatalf("Got an unexpected error: %q", err)
+	}
+	err = archive.Close()
+	if err != nil {
+		t.Fatalf("Got an unexpected error: %q", err)
+	}
+}
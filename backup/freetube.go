@@ -0,0 +1,126 @@
+package backup
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// freeTubeVideo is a line of FreeTube's history.db or a video in one of its playlists.
+type freeTubeVideo struct {
+	VideoID       string  `json:"videoId"`
+	Title         string  `json:"title"`
+	Author        string  `json:"author"`
+	LengthSeconds float64 `json:"lengthSeconds"`
+	// Published, TimeWatched and TimeAdded are Unix milliseconds.
+	Published     float64 `json:"published"`
+	TimeWatched   float64 `json:"timeWatched"`
+	WatchProgress float64 `json:"watchProgress"`
+	TimeAdded     float64 `json:"timeAdded"`
+}
+
+// freeTubePlaylist is a line of FreeTube's playlists.db.
+type freeTubePlaylist struct {
+	ID        string          `json:"_id"`
+	Name      string          `json:"playlistName"`
+	Videos    []freeTubeVideo `json:"videos"`
+	Protected bool            `json:"protected"`
+}
+
+// ReadFreeTube reads FreeTube's history.db or playlists.db, both hold one
+// JSON object per line. The videos of the "Watch Later" playlist are queued.
+func ReadFreeTube(filename string) ([]Item, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return parseFreeTube(file)
+}
+
+func parseFreeTube(r io.Reader) ([]Item, error) {
+	var items []Item
+	scanner := bufio.NewScanner(r)
+	// Playlists are single lines that easily exceed the default limit.
+	scanner.Buffer(nil, 64*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var fields map[string]json.RawMessage
+		err := json.Unmarshal(line, &fields)
+		if err != nil {
+			return nil, err
+		}
+		// FreeTube appends deletions and index definitions to its databases.
+		if _, ok := fields["$$deleted"]; ok {
+			continue
+		}
+
+		if _, ok := fields["videos"]; ok {
+			var playlist freeTubePlaylist
+			err = json.Unmarshal(line, &playlist)
+			if err != nil {
+				return nil, err
+			}
+			if !isWatchLater(playlist) {
+				continue
+			}
+			for _, vid := range playlist.Videos {
+				if vid.VideoID == "" {
+					continue
+				}
+				item := vid.item()
+				item.QueuedAt = millis(vid.TimeAdded)
+				if item.QueuedAt.IsZero() {
+					item.QueuedAt = time.Now()
+				}
+				items = append(items, item)
+			}
+			continue
+		}
+
+		if _, ok := fields["videoId"]; ok {
+			var vid freeTubeVideo
+			err = json.Unmarshal(line, &vid)
+			if err != nil {
+				return nil, err
+			}
+			if vid.VideoID == "" {
+				continue
+			}
+			item := vid.item()
+			item.WatchedAt = millis(vid.TimeWatched)
+			item.Position = int(vid.WatchProgress)
+			items = append(items, item)
+		}
+	}
+	return items, scanner.Err()
+}
+
+func isWatchLater(playlist freeTubePlaylist) bool {
+	return playlist.ID == "watchLater" || strings.EqualFold(playlist.Name, "Watch Later")
+}
+
+func (v freeTubeVideo) item() Item {
+	return Item{
+		VideoID:     v.VideoID,
+		Title:       v.Title,
+		Channel:     v.Author,
+		Duration:    int(v.LengthSeconds),
+		PublishedAt: millis(v.Published),
+	}
+}
+
+func millis(ms float64) time.Time {
+	if ms <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(int64(ms))
+}
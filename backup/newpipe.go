@@ -0,0 +1,146 @@
+package backup
+
+import (
+	"archive/zip"
+	"database/sql"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"time"
+)
+
+// newPipeYouTube is NewPipe's service ID for YouTube.
+const newPipeYouTube = 0
+
+// newPipeHistory selects the watched YouTube videos with the last time they
+// were watched and how far. NewPipe stores times in Unix milliseconds.
+const newPipeHistory = `
+select s.url, s.title, s.uploader, s.duration, coalesce(s.upload_date, 0),
+	max(h.access_date), coalesce(st.progress_time, 0)
+from streams s
+join stream_history h on h.stream_id = s.uid
+left join stream_state st on st.stream_id = s.uid
+where s.service_id = ?
+group by s.uid`
+
+// newPipeWatchLater selects the videos of local playlists named "Watch later",
+// NewPipe has no watch later list of its own.
+const newPipeWatchLater = `
+select s.url, s.title, s.uploader, s.duration, coalesce(s.upload_date, 0)
+from playlists p
+join playlist_stream_join j on j.playlist_id = p.uid
+join streams s on s.uid = j.stream_id
+where s.service_id = ?
+and lower(p.name) = 'watch later'
+order by j.join_index`
+
+// ReadNewPipe reads the history and the "Watch later" playlist of a NewPipe
+// backup zip, which holds the app's SQLite database.
+func ReadNewPipe(filename string) ([]Item, error) {
+	dbFile, err := extractNewPipeDB(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(dbFile)
+
+	db, err := sql.Open("sqlite", "file:"+dbFile+"?mode=ro")
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	var items []Item
+	rows, err := db.Query(newPipeHistory, newPipeYouTube)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var url string
+		var item Item
+		var uploaded, accessed, progress int64
+		err = rows.Scan(&url, &item.Title, &item.Channel, &item.Duration, &uploaded, &accessed, &progress)
+		if err != nil {
+			return nil, err
+		}
+		item.VideoID = videoIDFromURL(url)
+		if item.VideoID == "" {
+			continue
+		}
+		item.Short = isShortURL(url)
+		item.PublishedAt = millis(float64(uploaded))
+		item.WatchedAt = millis(float64(accessed))
+		item.Position = int(progress / 1000)
+		items = append(items, item)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err = db.Query(newPipeWatchLater, newPipeYouTube)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	// The queue is ordered by the time videos were queued, counting up keeps
+	// the order of the playlist.
+	queuedAt := time.Now()
+	for rows.Next() {
+		var url string
+		var item Item
+		var uploaded int64
+		err = rows.Scan(&url, &item.Title, &item.Channel, &item.Duration, &uploaded)
+		if err != nil {
+			return nil, err
+		}
+		item.VideoID = videoIDFromURL(url)
+		if item.VideoID == "" {
+			continue
+		}
+		item.Short = isShortURL(url)
+		item.PublishedAt = millis(float64(uploaded))
+		item.QueuedAt = queuedAt
+		queuedAt = queuedAt.Add(time.Second)
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// extractNewPipeDB copies the database out of the zip into a temporary
+// file, SQLite can't read it from the archive.
+func extractNewPipeDB(filename string) (string, error) {
+	archive, err := zip.OpenReader(filename)
+	if err != nil {
+		return "", err
+	}
+	defer archive.Close()
+
+	for _, file := range archive.File {
+		if path.Base(file.Name) != "newpipe.db" {
+			continue
+		}
+		src, err := file.Open()
+		if err != nil {
+			return "", err
+		}
+		defer src.Close()
+
+		dst, err := os.CreateTemp("", "newpipe-*.db")
+		if err != nil {
+			return "", err
+		}
+		_, err = io.Copy(dst, src)
+		closeErr := dst.Close()
+		if err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(dst.Name())
+			return "", err
+		}
+		return dst.Name(), nil
+	}
+	return "", errors.New("no newpipe.db in the backup")
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"image/color"
+	"strings"
+
+	"github.com/aaronzipp/deeptube/video"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// progressListeners are called with the new position whenever the position
+// of a video changes. It is only accessed from the Fyne goroutine.
+var progressListeners = make(map[string][]func(seconds int))
+
+func setProgress(videoID string, seconds int) {
+	for _, listener := range progressListeners[videoID] {
+		listener(seconds)
+	}
+}
+
+// videoCard is a single video shown in the feed.
+type videoCard struct {
+	video     video.Video
+	feed      *feed
+	object    fyne.CanvasObject
+	highlight *canvas.Rectangle
+	selection *canvas.Rectangle
+	watched   *widget.Icon
+	selected  bool
+}
+
+func newVideoCard(f *feed, vid video.Video) *videoCard {
+	c := &videoCard{video: vid, feed: f}
+
+	thumbnail := loadImage(vid.Thumbnail, f.thumbnailSize())
+	if len(vid.Thumbnail) == 0 {
+		pendingThumbnails[vid.VideoId] = append(pendingThumbnails[vid.VideoId], thumbnail)
+		thumbnailQueue.Enqueue(vid.VideoId, vid.ThumbnailUrl)
+	}
+
+	title := widget.NewLabelWithStyle(
+		vid.Title,
+		fyne.TextAlignLeading,
+		fyne.TextStyle{Bold: true},
+	)
+	title.Wrapping = fyne.TextWrapWord
+
+	channel := widget.NewLabelWithStyle(
+		vid.ChannelName,
+		fyne.TextAlignLeading,
+		fyne.TextStyle{Italic: true},
+	)
+	channel.Wrapping = fyne.TextWrapWord
+
+	infoBox := container.NewVBox(title, channel)
+	if playlists := vid.Playlists(); len(playlists) > 0 {
+		via := widget.NewLabelWithStyle(
+			"via "+strings.Join(playlists, ", "),
+			fyne.TextAlignLeading,
+			fyne.TextStyle{Italic: true},
+		)
+		via.Wrapping = fyne.TextWrapWord
+		infoBox.Add(via)
+	}
+
+	// The segments take their colors from the theme, so they follow theme changes.
+	segments := []widget.RichTextSegment{
+		&widget.TextSegment{Text: vid.VideoLength.String(), Style: widget.RichTextStyleInline},
+	}
+	if vid.WasLive {
+		segments = append(segments, &widget.TextSegment{Text: " LIVE", Style: widget.RichTextStyle{
+			ColorName: colorNameLive,
+			Inline:    true,
+			TextStyle: fyne.TextStyle{Bold: true},
+		}})
+	}
+	// Outside the Shorts view they only show up in the queue and playlists.
+	if vid.Type == video.ShortVideo && f.view != shortsView {
+		segments = append(segments, &widget.TextSegment{Text: " SHORT", Style: widget.RichTextStyle{
+			Inline:    true,
+			TextStyle: fyne.TextStyle{Bold: true},
+		}})
+	}
+	segments = append(segments,
+		&widget.TextSegment{Text: " • ", Style: widget.RichTextStyleInline},
+		&widget.TextSegment{Text: vid.TimeSincePublished(), Style: widget.RichTextStyle{
+			Inline:    true,
+			TextStyle: fyne.TextStyle{Italic: true},
+		}},
+	)
+	// In playlist views the videos are ordered by when they were added.
+	if !vid.AddedAt.IsZero() {
+		segments = append(segments, &widget.TextSegment{Text: ", added " + vid.TimeSinceAdded(), Style: widget.RichTextStyle{
+			Inline:    true,
+			TextStyle: fyne.TextStyle{Italic: true},
+		}})
+	}
+	if vid.IsNewSince(f.lastSeen) {
+		segments = append(segments, &widget.TextSegment{Text: " NEW", Style: widget.RichTextStyle{
+			ColorName: theme.ColorNamePrimary,
+			Inline:    true,
+			TextStyle: fyne.TextStyle{Bold: true},
+		}})
+	}
+	infoBox.Add(widget.NewRichText(segments...))
+
+	progress := widget.NewProgressBar()
+	progress.TextFormatter = func() string { return "" }
+	progress.SetValue(vid.Progress())
+	if vid.Position == 0 {
+		progress.Hide()
+	}
+	progressListeners[vid.VideoId] = append(progressListeners[vid.VideoId], func(seconds int) {
+		c.video.Position = seconds
+		progress.SetValue(c.video.Progress())
+		progress.Show()
+	})
+
+	watchBtn := widget.NewButtonWithIcon("", theme.MediaPlayIcon(), c.watch)
+
+	progressBtn := widget.NewButtonWithIcon("", theme.HistoryIcon(), func() {
+		showProgressDialog(f.window, c.video)
+	})
+
+	hideBtn := widget.NewButtonWithIcon("", theme.DeleteIcon(), c.hide)
+
+	c.watched = widget.NewIcon(theme.ConfirmIcon())
+	if !vid.Watched {
+		c.watched.Hide()
+	}
+
+	var content fyne.CanvasObject
+	if f.compact {
+		buttons := container.NewHBox(c.watched, watchBtn, progressBtn, hideBtn)
+		info := container.NewVBox(infoBox, progress)
+		content = container.NewBorder(nil, nil, thumbnail, container.NewCenter(buttons), info)
+	} else {
+		buttons := container.NewHBox(watchBtn, progressBtn, hideBtn, layout.NewSpacer(), c.watched)
+		// The thumbnail takes the extra height the grid adds to wider cards.
+		content = container.NewBorder(nil, buttons, nil, nil,
+			container.NewBorder(nil, container.NewVBox(progress, infoBox), nil, nil, thumbnail))
+	}
+
+	c.highlight = canvas.NewRectangle(color.Transparent)
+	c.highlight.StrokeWidth = 2
+	c.highlight.CornerRadius = theme.InputRadiusSize()
+	c.highlight.Hide()
+
+	c.selection = canvas.NewRectangle(color.Transparent)
+	c.selection.CornerRadius = theme.InputRadiusSize()
+	c.selection.Hide()
+	c.refreshColors()
+
+	c.object = container.NewPadded(container.NewStack(
+		c.selection,
+		c.highlight,
+		newTappable(container.NewPadded(content), func(modifier fyne.KeyModifier) {
+			if f.selecting {
+				f.toggleSelection(c, modifier&fyne.KeyModifierShift != 0)
+				return
+			}
+			f.focusCard(c)
+			c.showDetail()
+		}),
+	))
+	return c
+}
+
+// refreshColors applies the current theme to the rectangles, unlike widgets
+// they don't follow theme changes on their own.
+func (c *videoCard) refreshColors() {
+	c.highlight.StrokeColor = theme.Color(theme.ColorNamePrimary)
+	c.selection.FillColor = theme.Color(theme.ColorNameSelection)
+	c.highlight.Refresh()
+	c.selection.Refresh()
+}
+
+func (c *videoCard) setFocused(focused bool) {
+	if focused {
+		c.highlight.Show()
+	} else {
+		c.highlight.Hide()
+	}
+}
+
+func (c *videoCard) setSelected(selected bool) {
+	c.selected = selected
+	if selected {
+		c.selection.Show()
+	} else {
+		c.selection.Hide()
+	}
+}
+
+func (c *videoCard) setWatched(watched bool) {
+	c.video.Watched = watched
+	if watched {
+		c.watched.Show()
+	} else {
+		c.watched.Hide()
+	}
+}
+
+func (c *videoCard) watch() {
+	err := videoPlayer.Open(c.video, c.video.Position)
+	if err != nil {
+		dialog.ShowError(err, c.feed.window)
+	}
+}
+
+func (c *videoCard) showDetail() {
+	showDetail(c)
+}
+
+func (c *videoCard) hide() {
+	go func() {
+		c.video.Hide()
+	}()
+	c.feed.removeCard(c)
+}
+
+func (c *videoCard) toggleQueue() {
+	var err error
+	if c.video.Queued {
+		err = c.video.Unqueue()
+	} else {
+		err = c.video.Queue()
+	}
+	if err != nil {
+		dialog.ShowError(err, c.feed.window)
+		return
+	}
+	c.video.Queued = !c.video.Queued
+}
+
+func (c *videoCard) toggleWatched() {
+	var err error
+	if c.video.Watched {
+		err = c.video.MarkUnwatched()
+	} else {
+		err = c.video.MarkWatched()
+	}
+	if err != nil {
+		dialog.ShowError(err, c.feed.window)
+		return
+	}
+	c.setWatched(!c.video.Watched)
+}
+
+func showProgressDialog(w fyne.Window, vid video.Video) {
+	entry := widget.NewEntry()
+	entry.SetPlaceHolder("1:02:03")
+	if vid.Position > 0 {
+		entry.SetText(video.LengthFromSeconds(vid.Position).Timestamp())
+	}
+	entry.Validator = func(text string) error {
+		_, err := video.ParseTimestamp(text)
+		return err
+	}
+
+	items := []*widget.FormItem{widget.NewFormItem("Watched up to", entry)}
+	dialog.ShowForm("Mark progress", "Save", "Cancel", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		position, _ := video.ParseTimestamp(entry.Text)
+		seconds := position.TotalSeconds()
+		err := vid.SetPosition(seconds)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		setProgress(vid.VideoId, seconds)
+	}, w)
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aaronzipp/deeptube/backup"
+	"github.com/aaronzipp/deeptube/config"
+	"github.com/aaronzipp/deeptube/database"
+	"github.com/aaronzipp/deeptube/history"
+	"github.com/aaronzipp/deeptube/thumbnail"
+	"github.com/aaronzipp/deeptube/video"
+	"github.com/aaronzipp/deeptube/youtube"
+)
+
+const usage = `usage: deeptube [command]
+
+Without a command the tray application is started.
+
+commands:
+  maintenance    archive old videos, prune thumbnails and compact the database
+  import <file>  add the channels of a Takeout subscriptions.csv or an OPML file,
+                 or the history and watch later list of a NewPipe backup zip
+                 or FreeTube's history.db and playlists.db
+  export <format> [file]
+                 write the subscriptions and playlists as opml, newpipe, freetube
+                 or json to file or stdout
+  config check   validate config.yaml and the legacy feed files, listing every
+                 problem with its line
+  history [-format csv|json|jsonl] [-from date] [-until date] [-category name] [file]
+                 write all videos with their hidden, watched and queued state to
+                 file or stdout, dates like 2025-01-31 limit the publishing date`
+
+func runCommand(cfg config.Config, args []string) error {
+	switch args[0] {
+	case "maintenance":
+		return runMaintenance(cfg)
+	case "import":
+		if len(args) != 2 {
+			return errors.New(usage)
+		}
+		return runImport(cfg, args[1])
+	case "export":
+		if len(args) < 2 || len(args) > 3 {
+			return errors.New(usage)
+		}
+		return runExport(cfg, args[1], args[2:])
+	case "history":
+		return runHistory(args[1:])
+	case "help", "-h", "--help":
+		fmt.Println(usage)
+		return nil
+	default:
+		return errors.New(usage)
+	}
+}
+
+func runMaintenance(cfg config.Config) error {
+	archived, err := video.ApplyRetention(cfg.Retention)
+	if err != nil {
+		return fmt.Errorf("failed archiving videos: %w", err)
+	}
+	fmt.Printf("archived %d videos\n", archived)
+
+	removed, err := thumbnail.Prune(cfg.Thumbnails)
+	if err != nil {
+		return fmt.Errorf("failed pruning thumbnails: %w", err)
+	}
+	fmt.Printf("removed %d thumbnails\n", removed)
+
+	db, err := database.Open()
+	if err != nil {
+		return err
+	}
+	reclaimed, err := database.Vacuum(context.Background(), db)
+	if err != nil {
+		return fmt.Errorf("failed compacting the database: %w", err)
+	}
+	fmt.Printf("reclaimed %.1f MB\n", float64(reclaimed)/(1024*1024))
+
+	return nil
+}
+
+func runConfig(args []string) error {
+	if len(args) != 1 || args[0] != "check" {
+		return errors.New(usage)
+	}
+	cfg, err := config.Load(config.DefaultPath)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s is valid: %d subscriptions from %s, %d playlists from %s\n",
+		cfg.Path,
+		len(cfg.Subscriptions),
+		cfg.SubscriptionsPath,
+		len(cfg.Playlists),
+		cfg.PlaylistsPath,
+	)
+	return nil
+}
+
+func runImport(cfg config.Config, filename string) error {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".zip", ".db", ".json", ".ndjson":
+		return runBackupImport(filename)
+	}
+
+	added, err := youtube.ImportSubscriptions(filename, cfg)
+	if err != nil {
+		return err
+	}
+	for _, sub := range added {
+		fmt.Printf("added %s\n", sub.Channel)
+	}
+	fmt.Printf("imported %d subscriptions into %s\n", len(added), cfg.SubscriptionsPath)
+	return nil
+}
+
+func runExport(cfg config.Config, format string, args []string) error {
+	if len(args) == 0 {
+		return exportSubscriptions(os.Stdout, cfg, format)
+	}
+
+	file, err := os.Create(args[0])
+	if err != nil {
+		return err
+	}
+	err = exportSubscriptions(file, cfg, format)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// exportSubscriptions writes the subscriptions and playlists in format.
+func exportSubscriptions(w io.Writer, cfg config.Config, format string) error {
+	return youtube.Export(w, format, cfg.Subscriptions, cfg.Playlists)
+}
+
+func runHistory(args []string) error {
+	flags := flag.NewFlagSet("history", flag.ContinueOnError)
+	format := flags.String("format", history.FormatCSV, "csv, json or jsonl")
+	from := flags.String("from", "", "only videos published on or after this date")
+	until := flags.String("until", "", "only videos published before this date")
+	category := flags.String("category", "", "only videos in this category")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+	if flags.NArg() > 1 {
+		return errors.New(usage)
+	}
+
+	filter := history.Filter{Category: *category}
+	filter.From, err = parseDate(*from)
+	if err != nil {
+		return err
+	}
+	filter.Until, err = parseDate(*until)
+	if err != nil {
+		return err
+	}
+
+	entries, err := history.Load(filter)
+	if err != nil {
+		return err
+	}
+
+	if flags.NArg() == 0 {
+		return history.Write(os.Stdout, *format, entries)
+	}
+	file, err := os.Create(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	err = history.Write(file, *format, entries)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// parseDate parses dates like 2025-01-31 in local time, empty ones are zero.
+func parseDate(date string) (time.Time, error) {
+	if date == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", date, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q, expected e.g. 2025-01-31", date)
+	}
+	return t, nil
+}
+
+func runBackupImport(filename string) error {
+	result, err := backup.ImportFile(filename)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("marked %d videos as watched and queued %d, %d of them were new\n",
+		result.Watched, result.Queued, result.Added)
+	return nil
+}
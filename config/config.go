@@ -0,0 +1,239 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// Version is the version of the config file format this build understands.
+const Version = 1
+
+const DefaultPath = "config.yaml"
+
+// Before config.yaml held everything, the feeds and the API key had files of
+// their own. They are still read as long as config.yaml doesn't replace them.
+const (
+	LegacySubscriptionsPath = "subscriptions.yaml"
+	LegacyPlaylistsPath     = "playlists.yaml"
+	legacyEnvPath           = ".env"
+)
+
+type Config struct {
+	Version       int            `yaml:"version"`
+	API           API            `yaml:"api"`
+	Subscriptions []Subscription `yaml:"subscriptions"`
+	Playlists     []Playlist     `yaml:"playlists"`
+	Refresh       Refresh        `yaml:"refresh"`
+	Thumbnails    Thumbnails     `yaml:"thumbnails"`
+	Retention     Retention      `yaml:"retention"`
+	Player        Player         `yaml:"player"`
+	UI            UI             `yaml:"ui"`
+
+	// Path is the file the config was loaded from, SubscriptionsPath and
+	// PlaylistsPath the files the feeds were read from, either Path or the
+	// legacy files.
+	Path              string `yaml:"-"`
+	SubscriptionsPath string `yaml:"-"`
+	PlaylistsPath     string `yaml:"-"`
+}
+
+// API holds the YouTube Data API key. Without it in the config the
+// YOUTUBE_API_KEY environment variable or the legacy .env file is used.
+type API struct {
+	Key string `yaml:"key"`
+}
+
+type Refresh struct {
+	Interval   time.Duration `yaml:"interval"`
+	MaxBackoff time.Duration `yaml:"max_backoff"`
+	QuietHours QuietHours    `yaml:"quiet_hours"`
+}
+
+// QuietHours is a daily window, given in local hours, during which scheduled
+// refreshes are skipped. Start and End being equal disables it.
+type QuietHours struct {
+	Start int `yaml:"start"`
+	End   int `yaml:"end"`
+}
+
+// Thumbnails limits the space used by stored thumbnails, zero disables a limit.
+// Thumbnails of hidden videos are always removed.
+type Thumbnails struct {
+	MaxAgeDays int `yaml:"max_age_days"`
+	MaxSizeMB  int `yaml:"max_size_mb"`
+}
+
+// Retention controls how long videos are kept. Videos that were watched or
+// queued are kept forever, all others are archived and removed once they were
+// published and fetched more than UnseenDays ago. Zero keeps every video.
+type Retention struct {
+	UnseenDays int `yaml:"unseen_days"`
+}
+
+// Player decides how videos are opened. A launcher configured for one of the
+// video's categories takes precedence over the default one.
+type Player struct {
+	Default    Launcher            `yaml:"default"`
+	Categories map[string]Launcher `yaml:"categories"`
+}
+
+// UI holds the defaults of the window, changes made in the window are
+// remembered separately and take precedence.
+type UI struct {
+	// Videos is the number of videos shown in the feed.
+	Videos int `yaml:"videos"`
+	// View is either "grid" or "list".
+	View string `yaml:"view"`
+}
+
+const (
+	ViewGrid = "grid"
+	ViewList = "list"
+)
+
+// Launcher either opens URL in the browser or runs Command, which is split on
+// whitespace. Both may contain the placeholders {url}, {id} and {start}, the
+// YouTube link, the video ID and the second to start playing at.
+type Launcher struct {
+	URL     string `yaml:"url"`
+	Command string `yaml:"command"`
+}
+
+func Default() Config {
+	return Config{
+		Version: Version,
+		Refresh: Refresh{
+			Interval:   30 * time.Minute,
+			MaxBackoff: 6 * time.Hour,
+		},
+		Thumbnails: Thumbnails{
+			MaxAgeDays: 90,
+			MaxSizeMB:  200,
+		},
+		Retention: Retention{
+			UnseenDays: 365,
+		},
+		Player: Player{
+			Default: Launcher{URL: "{url}"},
+		},
+		UI: UI{
+			Videos: 20,
+			View:   ViewGrid,
+		},
+	}
+}
+
+// Load reads the config file, falling back to the defaults for a missing file
+// and for every setting that is left out. Unknown keys and invalid values
+// are reported with their line, all problems at once.
+func Load(filename string) (Config, error) {
+	cfg := Default()
+	cfg.Path = filename
+	cfg.SubscriptionsPath = filename
+	cfg.PlaylistsPath = filename
+
+	var v validator
+	root, err := v.decodeFile(filename, &cfg)
+	if err != nil {
+		return cfg, err
+	}
+	files := map[string]*yaml.Node{filename: root}
+
+	if lookup(root, "subscriptions") == nil {
+		legacy, err := v.decodeFile(LegacySubscriptionsPath, &cfg.Subscriptions)
+		if err != nil {
+			return cfg, err
+		}
+		if legacy != nil {
+			cfg.SubscriptionsPath = LegacySubscriptionsPath
+			files[LegacySubscriptionsPath] = legacy
+		}
+	}
+	if lookup(root, "playlists") == nil {
+		legacy, err := v.decodeFile(LegacyPlaylistsPath, &cfg.Playlists)
+		if err != nil {
+			return cfg, err
+		}
+		if legacy != nil {
+			cfg.PlaylistsPath = LegacyPlaylistsPath
+			files[LegacyPlaylistsPath] = legacy
+		}
+	}
+
+	// Playlists kept their live streams before they had the setting, so
+	// leaving it out keeps them.
+	playlists := files[cfg.PlaylistsPath]
+	if cfg.PlaylistsPath == cfg.Path {
+		playlists = lookup(root, "playlists")
+	}
+	for i := range cfg.Playlists {
+		if lookup(item(playlists, i), "live") == nil {
+			cfg.Playlists[i].Live = true
+		}
+	}
+
+	// A default player in the file replaces the built-in one instead of
+	// adding a command to its url.
+	if node := lookup(root, "player", "default"); node != nil {
+		var launcher Launcher
+		if node.Decode(&launcher) == nil {
+			cfg.Player.Default = launcher
+		}
+	}
+
+	if cfg.API.Key == "" {
+		cfg.API.Key = os.Getenv("YOUTUBE_API_KEY")
+	}
+	if cfg.API.Key == "" {
+		env, err := godotenv.Read(legacyEnvPath)
+		if err == nil {
+			cfg.API.Key = env["YOUTUBE_API_KEY"]
+		}
+	}
+
+	return cfg, v.validate(cfg, files)
+}
+
+// decodeFile decodes the YAML file into out and returns its document node
+// for looking up lines. Unknown keys and values of the wrong type are
+// recorded as problems, the rest of the file is decoded anyway. Missing
+// files are no error, their node is nil.
+func (v *validator) decodeFile(filename string, out any) (*yaml.Node, error) {
+	data, err := os.ReadFile(filename)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var root yaml.Node
+	err = yaml.Unmarshal(data, &root)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	if root.Kind == 0 {
+		// The file is empty.
+		return &root, nil
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	err = decoder.Decode(out)
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) {
+		v.addTypeErrors(filename, typeErr)
+		return &root, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return &root, nil
+}
@@ -0,0 +1,279 @@
+package config
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeFiles writes the files into a new working directory.
+func writeFiles(t *testing.T, files map[string]string) {
+	t.Chdir(t.TempDir())
+	for filename, content := range files {
+		err := os.WriteFile(filename, []byte(content), 0o644)
+		if err != nil {
+			t.Fatalf("Got an unexpected error: %q", err)
+		}
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  []string
+	}{
+		{
+			name:  "valid",
+			files: map[string]string{DefaultPath: "version: 1\nsubscriptions:\n  - channel: A\n    id: UCa\n"},
+		},
+		{
+			name:  "missing",
+			files: map[string]string{},
+		},
+		{
+			name: "unknown keys",
+			files: map[string]string{
+				DefaultPath:             "version: 1\nrefresh:\n  intervall: 1h\nui:\n  videos: 0\n",
+				LegacySubscriptionsPath: "- channel: A\n  id: UCa\n  live: maybe\n",
+			},
+			want: []string{
+				`config.yaml:3: unknown key "intervall"`,
+				"config.yaml:5: ui videos must be positive, got 0",
+				"subscriptions.yaml:3: cannot unmarshal !!str `maybe` into bool",
+			},
+		},
+		{
+			name:  "newer version",
+			files: map[string]string{DefaultPath: "version: 2\n"},
+			want:  []string{"config.yaml:1: unsupported version 2"},
+		},
+		{
+			name: "ids",
+			files: map[string]string{DefaultPath: `subscriptions:
+  - channel: A
+    id: UCa
+  - channel: B
+    id: PLb
+playlists:
+  - playlist: C
+    id: UCa
+`},
+			want: []string{
+				`config.yaml:5: subscription id "PLb" must start with UC`,
+				`config.yaml:8: playlist id "UCa" must start with PL`,
+				`config.yaml:8: duplicate id "UCa", already used at config.yaml:3`,
+			},
+		},
+		{
+			name: "legacy files",
+			files: map[string]string{
+				DefaultPath:             "ui:\n  view: tiles\n",
+				LegacySubscriptionsPath: "- channel: A\n  id: UCa\n\n- channel: A\n  id: UCa\n",
+			},
+			want: []string{
+				`subscriptions.yaml:5: duplicate id "UCa", already used at subscriptions.yaml:2`,
+				`config.yaml:2: ui view must be "grid" or "list", got "tiles"`,
+			},
+		},
+		{
+			name: "feed settings",
+			files: map[string]string{DefaultPath: `playlists:
+  - playlist: A
+    id: PLa
+    max_items: -1
+    reverse: true
+    track_added: true
+`},
+			want: []string{
+				"config.yaml:4: max_items must not be negative",
+				"config.yaml:5: reverse and track_added can't be combined",
+			},
+		},
+		{
+			name: "players",
+			files: map[string]string{DefaultPath: `player:
+  default:
+    url: "{url}"
+    command: mpv {url}
+  categories:
+    Music:
+      command: "  "
+`},
+			want: []string{
+				"config.yaml:3: the default player needs either a url or a command",
+				`config.yaml:7: the player for category "Music" needs either a url or a command`,
+			},
+		},
+		{
+			name:  "command as default player",
+			files: map[string]string{DefaultPath: "player:\n  default:\n    command: mpv {url}\n"},
+		},
+		{
+			name:  "settings",
+			files: map[string]string{DefaultPath: "refresh:\n  interval: 0s\nui:\n  videos: 0\n"},
+			want: []string{
+				"config.yaml:2: refresh interval must be positive",
+				"config.yaml:4: ui videos must be positive",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			writeFiles(t, test.files)
+			_, err := Load(DefaultPath)
+			if len(test.want) == 0 {
+				if err != nil {
+					t.Fatalf("Got an unexpected error: %q", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Got no error, want %q", test.want)
+			}
+			lines := strings.Split(err.Error(), "\n")
+			for _, want := range test.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Got\n%s\nwant it to contain %q", err, want)
+				}
+			}
+			if len(lines) != len(test.want) {
+				t.Errorf("Got %d errors, want %d:\n%s", len(lines), len(test.want), err)
+			}
+		})
+	}
+}
+
+func TestLoadLegacy(t *testing.T) {
+	writeFiles(t, map[string]string{
+		LegacySubscriptionsPath: "- channel: A\n  id: UCa\n",
+		LegacyPlaylistsPath:     "- playlist: B\n  id: PLb\n",
+		legacyEnvPath:           "YOUTUBE_API_KEY=secret\n",
+	})
+	t.Setenv("YOUTUBE_API_KEY", "")
+
+	cfg, err := Load(DefaultPath)
+	if err != nil {
+		t.Fatalf("Got an unexpected error: %q", err)
+	}
+	if len(cfg.Subscriptions) != 1 || cfg.SubscriptionsPath != LegacySubscriptionsPath {
+		t.Errorf("Got subscriptions %+v from %s", cfg.Subscriptions, cfg.SubscriptionsPath)
+	}
+	if len(cfg.Playlists) != 1 || cfg.PlaylistsPath != LegacyPlaylistsPath {
+		t.Errorf("Got playlists %+v from %s", cfg.Playlists, cfg.PlaylistsPath)
+	}
+	if cfg.API.Key != "secret" {
+		t.Errorf("Got API key %q, want the one from .env", cfg.API.Key)
+	}
+}
+
+func TestPlaylistLive(t *testing.T) {
+	writeFiles(t, map[string]string{DefaultPath: `playlists:
+  - playlist: A
+    id: PLa
+  - playlist: B
+    id: PLb
+    live: false
+  - playlist: C
+    id: PLc
+    lives: true
+`})
+
+	cfg, err := Load(DefaultPath)
+	if err == nil || !strings.Contains(err.Error(), `config.yaml:9: unknown key "lives"`) {
+		t.Errorf("Got %v, want the unknown key in the playlist", err)
+	}
+	got := []bool{cfg.Playlists[0].Live, cfg.Playlists[1].Live, cfg.Playlists[2].Live}
+	want := []bool{true, false, true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %+v, want %+v", got, want)
+	}
+}
+
+func TestAppendSubscriptions(t *testing.T) {
+	subs := []Subscription{
+		{Channel: "Compilers", Feed: Feed{ID: "UC0000000000000000000001"}},
+		{Channel: "Cooking", Feed: Feed{ID: "UC0000000000000000000002"}},
+		{Channel: "Cooking", Feed: Feed{ID: "UC0000000000000000000002"}},
+	}
+	tests := []struct {
+		name     string
+		filename string
+		existing string
+	}{
+		{
+			name:     "legacy",
+			filename: LegacySubscriptionsPath,
+			existing: "# my channels\n- channel: Compilers\n  id: UC0000000000000000000001\n  categories: [Tech]\n",
+		},
+		{
+			name:     "config",
+			filename: DefaultPath,
+			existing: "version: 1\n# my channels\nsubscriptions:\n  - channel: Compilers\n    id: UC0000000000000000000001\n",
+		},
+		{
+			name:     "new config",
+			filename: DefaultPath,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			files := map[string]string{}
+			if test.existing != "" {
+				files[test.filename] = test.existing
+			}
+			writeFiles(t, files)
+			cfg, err := Load(DefaultPath)
+			if err != nil {
+				t.Fatalf("Got an unexpected error: %q", err)
+			}
+
+			added, err := AppendSubscriptions(cfg, subs)
+			if err != nil {
+				t.Fatalf("Got an unexpected error: %q", err)
+			}
+			want := 1
+			if test.existing == "" {
+				want = 2
+			}
+			if len(added) != want {
+				t.Errorf("Got %+v, want %d added", added, want)
+			}
+
+			cfg, err = Load(DefaultPath)
+			if err != nil {
+				t.Fatalf("Got an unexpected error: %q", err)
+			}
+			data, _ := os.ReadFile(test.filename)
+			if len(cfg.Subscriptions) != 2 || !strings.Contains(string(data), "# my channels") && test.existing != "" {
+				t.Errorf("Got %+v in\n%s", cfg.Subscriptions, data)
+			}
+		})
+	}
+}
+
+func TestAddedFeeds(t *testing.T) {
+	old := Config{
+		Subscriptions: []Subscription{{Feed: Feed{ID: "UCa"}}, {Feed: Feed{ID: "UCb", Live: true}}},
+		Playlists:     []Playlist{{Feed: Feed{ID: "PLa"}}},
+	}
+	cfg := Config{
+		Subscriptions: []Subscription{
+			{Feed: Feed{ID: "UCa"}, Shorts: true},
+			{Feed: Feed{ID: "UCb"}},
+			{Feed: Feed{ID: "UCc"}},
+		},
+		Playlists: []Playlist{{Feed: Feed{ID: "PLa", MaxItems: 20}}, {Feed: Feed{ID: "PLb"}}},
+	}
+
+	subs, playlists := AddedFeeds(old, cfg)
+	if len(subs) != 2 || subs[0].ID != "UCa" || subs[1].ID != "UCc" {
+		t.Errorf("Got subscriptions %+v, want UCa and UCc", subs)
+	}
+	if len(playlists) != 2 {
+		t.Errorf("Got playlists %+v, want PLa and PLb", playlists)
+	}
+}
@@ -0,0 +1,221 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultMaxItems is the number of videos fetched per feed and refresh when
+// max_items isn't set.
+const DefaultMaxItems = 10
+
+// Feed holds the settings subscriptions and playlists have in common.
+type Feed struct {
+	ID         string   `yaml:"id" json:"id"`
+	Categories []string `yaml:"categories" json:"categories"`
+	// Live fetches the past live streams of a channel as well. Playlists
+	// keep their live streams and premieres unless it is set to false.
+	Live            bool     `yaml:"live,omitempty" json:"live,omitempty"`
+	ExcludeKeywords []string `yaml:"exclude_keywords,omitempty" json:"exclude_keywords,omitempty"`
+	Notify          bool     `yaml:"notify,omitempty" json:"notify,omitempty"`
+	// MaxItems is the number of videos fetched per refresh, zero means DefaultMaxItems.
+	MaxItems int `yaml:"max_items,omitempty" json:"max_items,omitempty"`
+}
+
+type Subscription struct {
+	Channel string `yaml:"channel" json:"channel"`
+	Feed    `yaml:",inline"`
+	Shorts  bool `yaml:"shorts,omitempty" json:"shorts,omitempty"`
+}
+
+type Playlist struct {
+	Playlist string `yaml:"playlist" json:"playlist"`
+	Feed     `yaml:",inline"`
+	// Reverse fetches the videos from the end, for playlists ordered oldest first.
+	Reverse bool `yaml:"reverse,omitempty" json:"reverse,omitempty"`
+	// TrackAdded fetches the videos added last, wherever they are in the playlist.
+	TrackAdded bool `yaml:"track_added,omitempty" json:"track_added,omitempty"`
+}
+
+// Items returns the number of videos to fetch per refresh.
+func (f Feed) Items() int {
+	if f.MaxItems == 0 {
+		return DefaultMaxItems
+	}
+	return f.MaxItems
+}
+
+// AppendSubscriptions adds the subscriptions whose channel isn't in the
+// config yet to the end of the file the subscriptions were read from, leaving
+// the existing entries and comments untouched. It returns the ones added.
+func AppendSubscriptions(cfg Config, subs []Subscription) ([]Subscription, error) {
+	if cfg.SubscriptionsPath != cfg.Path {
+		return appendToList(cfg.SubscriptionsPath, subs)
+	}
+	return appendToConfig(cfg.Path, subs)
+}
+
+// newSubscriptions drops the subscriptions that are in existing or repeated.
+func newSubscriptions(existing, subs []Subscription) []Subscription {
+	known := make(map[string]bool)
+	for _, sub := range existing {
+		known[sub.ID] = true
+	}
+	var added []Subscription
+	for _, sub := range subs {
+		if known[sub.ID] {
+			continue
+		}
+		known[sub.ID] = true
+		added = append(added, sub)
+	}
+	return added
+}
+
+// appendToList appends to a legacy file holding only the list of subscriptions.
+func appendToList(filename string, subs []Subscription) ([]Subscription, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	var existing []Subscription
+	err = yaml.Unmarshal(data, &existing)
+	if err != nil {
+		return nil, err
+	}
+
+	added := newSubscriptions(existing, subs)
+	if len(added) == 0 {
+		return nil, nil
+	}
+
+	addedData, err := yaml.Marshal(added)
+	if err != nil {
+		return nil, err
+	}
+	// An empty list may be written as "[]", which can't be continued.
+	if len(existing) == 0 {
+		data = nil
+	}
+	if len(data) > 0 && !bytes.HasSuffix(data, []byte("\n")) {
+		data = append(data, '\n')
+	}
+	return added, os.WriteFile(filename, append(data, addedData...), 0o644)
+}
+
+// appendToConfig appends to the subscriptions of config.yaml, creating the
+// file or the key if they are missing.
+func appendToConfig(filename string, subs []Subscription) ([]Subscription, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	var root yaml.Node
+	err = yaml.Unmarshal(data, &root)
+	if err != nil {
+		return nil, err
+	}
+	if root.Kind == 0 {
+		root = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{
+			Kind: yaml.MappingNode,
+			Content: []*yaml.Node{
+				{Kind: yaml.ScalarNode, Value: "version"},
+				{Kind: yaml.ScalarNode, Value: strconv.Itoa(Version)},
+			},
+		}}}
+	}
+	mapping := root.Content[0]
+	if mapping.Kind != yaml.MappingNode {
+		return nil, errors.New(filename + ": expected a mapping at the top level")
+	}
+
+	list := lookup(&root, "subscriptions")
+	if list == nil {
+		list = &yaml.Node{Kind: yaml.SequenceNode}
+		mapping.Content = append(mapping.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: "subscriptions"},
+			list,
+		)
+	}
+
+	var existing []Subscription
+	err = list.Decode(&existing)
+	if err != nil {
+		return nil, err
+	}
+	if list.Kind == yaml.ScalarNode && list.Tag == "!!null" {
+		// "subscriptions:" without any entries.
+		*list = yaml.Node{Kind: yaml.SequenceNode, Line: list.Line, Column: list.Column}
+	}
+	added := newSubscriptions(existing, subs)
+	if len(added) == 0 {
+		return nil, nil
+	}
+
+	for _, sub := range added {
+		var node yaml.Node
+		err = node.Encode(sub)
+		if err != nil {
+			return nil, err
+		}
+		list.Content = append(list.Content, &node)
+	}
+	// An empty list may be written as "[]", which can't hold the new entries.
+	list.Style = 0
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	err = encoder.Encode(&root)
+	if err != nil {
+		return nil, err
+	}
+	err = encoder.Close()
+	if err != nil {
+		return nil, err
+	}
+	return added, os.WriteFile(filename, buf.Bytes(), 0o644)
+}
+
+// AddedFeeds returns the subscriptions and playlists of cfg whose videos
+// weren't fetched with old, i.e. new ones and those whose settings now select
+// more or other videos.
+func AddedFeeds(old, cfg Config) ([]Subscription, []Playlist) {
+	oldSubs := make(map[string]Subscription)
+	for _, sub := range old.Subscriptions {
+		oldSubs[sub.ID] = sub
+	}
+	var subs []Subscription
+	for _, sub := range cfg.Subscriptions {
+		before, ok := oldSubs[sub.ID]
+		if !ok || sub.Feed.selectsMore(before.Feed) || sub.Shorts && !before.Shorts {
+			subs = append(subs, sub)
+		}
+	}
+
+	oldPlaylists := make(map[string]Playlist)
+	for _, playlist := range old.Playlists {
+		oldPlaylists[playlist.ID] = playlist
+	}
+	var playlists []Playlist
+	for _, playlist := range cfg.Playlists {
+		before, ok := oldPlaylists[playlist.ID]
+		if !ok || playlist.Feed.selectsMore(before.Feed) ||
+			playlist.Reverse != before.Reverse || playlist.TrackAdded != before.TrackAdded {
+			playlists = append(playlists, playlist)
+		}
+	}
+	return subs, playlists
+}
+
+// selectsMore reports whether f takes videos before didn't.
+func (f Feed) selectsMore(before Feed) bool {
+	return f.Live && !before.Live || f.Items() > before.Items()
+}
@@ -0,0 +1,199 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// validator collects the problems of a config together with where they are.
+type validator struct {
+	errs []error
+}
+
+// addf records a problem at node of filename, node may be nil if the setting
+// wasn't in the file.
+func (v *validator) addf(filename string, node *yaml.Node, format string, args ...any) {
+	location := filename
+	if node != nil {
+		location += ":" + strconv.Itoa(node.Line)
+	}
+	v.errs = append(v.errs, fmt.Errorf("%s: %s", location, fmt.Sprintf(format, args...)))
+}
+
+// typeErrorPattern splits the messages of yaml.TypeError into line and problem.
+var typeErrorPattern = regexp.MustCompile(`^line (\d+): (.*)$`)
+
+// unknownFieldPattern matches the problem of an unknown key.
+var unknownFieldPattern = regexp.MustCompile(`^field (\S+) not found in type \S+$`)
+
+// addTypeErrors records the problems yaml found while decoding filename.
+func (v *validator) addTypeErrors(filename string, err *yaml.TypeError) {
+	for _, msg := range err.Errors {
+		var node *yaml.Node
+		if match := typeErrorPattern.FindStringSubmatch(msg); match != nil {
+			line, _ := strconv.Atoi(match[1])
+			node = &yaml.Node{Line: line}
+			msg = match[2]
+		}
+		if match := unknownFieldPattern.FindStringSubmatch(msg); match != nil {
+			msg = fmt.Sprintf("unknown key %q", match[1])
+		}
+		v.addf(filename, node, "%s", msg)
+	}
+}
+
+// validate checks the decoded config and returns all problems, including
+// the ones recorded while decoding.
+func (v *validator) validate(cfg Config, files map[string]*yaml.Node) error {
+	root := files[cfg.Path]
+	at := func(path ...string) *yaml.Node {
+		return lookup(root, path...)
+	}
+
+	if cfg.Version < 0 || cfg.Version > Version {
+		v.addf(cfg.Path, at("version"), "unsupported version %d, this build understands version %d", cfg.Version, Version)
+	}
+
+	// ids holds where each id was first used.
+	ids := make(map[string]string)
+	checkID := func(filename string, item *yaml.Node, id, kind string, prefixes ...string) {
+		node := lookup(item, "id")
+		if node == nil {
+			node = item
+		}
+		switch {
+		case id == "":
+			v.addf(filename, node, "%s without id", kind)
+			return
+		case !hasPrefix(id, prefixes):
+			v.addf(filename, node, "%s id %q must start with %s", kind, id, strings.Join(prefixes, " or "))
+		}
+		if first, ok := ids[id]; ok {
+			v.addf(filename, node, "duplicate id %q, already used at %s", id, first)
+			return
+		}
+		ids[id] = filename
+		if node != nil {
+			ids[id] += ":" + strconv.Itoa(node.Line)
+		}
+	}
+	subscriptions := files[cfg.SubscriptionsPath]
+	if cfg.SubscriptionsPath == cfg.Path {
+		subscriptions = lookup(root, "subscriptions")
+	}
+	checkFeed := func(filename string, item *yaml.Node, feed Feed) {
+		if feed.MaxItems < 0 {
+			v.addf(filename, lookup(item, "max_items"), "max_items must not be negative, got %d", feed.MaxItems)
+		}
+	}
+	for i, sub := range cfg.Subscriptions {
+		checkID(cfg.SubscriptionsPath, item(subscriptions, i), sub.ID, "subscription", "UC")
+		checkFeed(cfg.SubscriptionsPath, item(subscriptions, i), sub.Feed)
+	}
+	playlists := files[cfg.PlaylistsPath]
+	if cfg.PlaylistsPath == cfg.Path {
+		playlists = lookup(root, "playlists")
+	}
+	for i, playlist := range cfg.Playlists {
+		checkID(cfg.PlaylistsPath, item(playlists, i), playlist.ID, "playlist", "PL")
+		checkFeed(cfg.PlaylistsPath, item(playlists, i), playlist.Feed)
+		if playlist.Reverse && playlist.TrackAdded {
+			v.addf(cfg.PlaylistsPath, lookup(item(playlists, i), "reverse"), "reverse and track_added can't be combined")
+		}
+	}
+
+	if cfg.Refresh.Interval <= 0 {
+		v.addf(cfg.Path, at("refresh", "interval"), "refresh interval must be positive, got %s", cfg.Refresh.Interval)
+	} else if cfg.Refresh.MaxBackoff < cfg.Refresh.Interval {
+		v.addf(cfg.Path, at("refresh", "max_backoff"),
+			"refresh max_backoff %s is shorter than the interval %s",
+			cfg.Refresh.MaxBackoff,
+			cfg.Refresh.Interval,
+		)
+	}
+	for _, key := range []string{"start", "end"} {
+		hour := cfg.Refresh.QuietHours.Start
+		if key == "end" {
+			hour = cfg.Refresh.QuietHours.End
+		}
+		if hour < 0 || hour > 23 {
+			v.addf(cfg.Path, at("refresh", "quiet_hours", key), "quiet hours must be between 0 and 23, got %d", hour)
+		}
+	}
+	if cfg.Thumbnails.MaxAgeDays < 0 {
+		v.addf(cfg.Path, at("thumbnails", "max_age_days"), "thumbnail limits must not be negative")
+	}
+	if cfg.Thumbnails.MaxSizeMB < 0 {
+		v.addf(cfg.Path, at("thumbnails", "max_size_mb"), "thumbnail limits must not be negative")
+	}
+	if cfg.Retention.UnseenDays < 0 {
+		v.addf(cfg.Path, at("retention", "unseen_days"), "retention unseen_days must not be negative")
+	}
+	if !cfg.Player.Default.valid() {
+		v.addf(cfg.Path, at("player", "default"), "the default player needs either a url or a command")
+	}
+	for category, launcher := range cfg.Player.Categories {
+		if !launcher.valid() {
+			v.addf(cfg.Path, at("player", "categories", category),
+				"the player for category %q needs either a url or a command", category)
+		}
+	}
+	if cfg.UI.Videos <= 0 {
+		v.addf(cfg.Path, at("ui", "videos"), "ui videos must be positive, got %d", cfg.UI.Videos)
+	}
+	if cfg.UI.View != ViewGrid && cfg.UI.View != ViewList {
+		v.addf(cfg.Path, at("ui", "view"), "ui view must be %q or %q, got %q", ViewGrid, ViewList, cfg.UI.View)
+	}
+
+	return errors.Join(v.errs...)
+}
+
+// valid reports whether the launcher has exactly one of URL and Command.
+func (l Launcher) valid() bool {
+	return (strings.TrimSpace(l.URL) == "") != (strings.TrimSpace(l.Command) == "")
+}
+
+func hasPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// lookup follows the keys through nested mappings and returns the value node,
+// nil if any of them is missing.
+func lookup(node *yaml.Node, keys ...string) *yaml.Node {
+	if node != nil && node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	for _, key := range keys {
+		if node == nil || node.Kind != yaml.MappingNode {
+			return nil
+		}
+		var value *yaml.Node
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == key {
+				value = node.Content[i+1]
+				break
+			}
+		}
+		node = value
+	}
+	return node
+}
+
+// item returns the i-th element of a sequence, nil if there is none.
+func item(node *yaml.Node, i int) *yaml.Node {
+	node = lookup(node)
+	if node == nil || node.Kind != yaml.SequenceNode || i >= len(node.Content) {
+		return nil
+	}
+	return node.Content[i]
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"log"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// settleDelay is how long the files have to stay unchanged before they are
+// reloaded, editors often write a file in several steps.
+const settleDelay = 200 * time.Millisecond
+
+// Watcher reloads the config whenever config.yaml or one of the legacy files
+// next to it changes.
+type Watcher struct {
+	path     string
+	files    map[string]bool
+	watcher  *fsnotify.Watcher
+	onChange func(Config, error)
+
+	mu    sync.Mutex
+	timer *time.Timer
+}
+
+// Watch calls onChange with the reloaded config, or the problems found in
+// it, after the files changed. The directory is watched instead of the files,
+// so files being replaced or created later are noticed as well.
+func Watch(filename string, onChange func(Config, error)) (*Watcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	err = watcher.Add(filepath.Dir(filename))
+	if err != nil {
+		watcher.Close()
+		return nil, err
+	}
+
+	w := &Watcher{
+		path:     filename,
+		files:    make(map[string]bool),
+		watcher:  watcher,
+		onChange: onChange,
+	}
+	for _, name := range []string{filename, LegacySubscriptionsPath, LegacyPlaylistsPath, legacyEnvPath} {
+		w.files[filepath.Clean(name)] = true
+	}
+	go w.loop()
+	return w, nil
+}
+
+// Close stops watching, a reload already scheduled may still happen.
+func (w *Watcher) Close() error {
+	return w.watcher.Close()
+}
+
+func (w *Watcher) loop() {
+	for {
+		select {
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Op == fsnotify.Chmod || !w.files[filepath.Clean(event.Name)] {
+				continue
+			}
+			w.schedule()
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("failed watching %s: %s", w.path, err)
+		}
+	}
+}
+
+// schedule reloads the config once the files settled.
+func (w *Watcher) schedule() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	w.timer = time.AfterFunc(settleDelay, func() {
+		w.onChange(Load(w.path))
+	})
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestWatch(t *testing.T) {
+	writeFiles(t, map[string]string{DefaultPath: "version: 1\n"})
+
+	type reload struct {
+		cfg Config
+		err error
+	}
+	reloads := make(chan reload, 10)
+	w, err := Watch(DefaultPath, func(cfg Config, err error) {
+		reloads <- reload{cfg, err}
+	})
+	if err != nil {
+		t.Fatalf("Got an unexpected error: %q", err)
+	}
+	defer w.Close()
+
+	next := func() reload {
+		select {
+		case r := <-reloads:
+			return r
+		case <-time.After(5 * time.Second):
+			t.Fatal("Config wasn't reloaded")
+			return reload{}
+		}
+	}
+
+	err = os.WriteFile(LegacySubscriptionsPath, []byte("- channel: A\n  id: UCa\n"), 0o644)
+	if err != nil {
+		t.Fatalf("Got an unexpected error: %q", err)
+	}
+	r := next()
+	if r.err != nil || len(r.cfg.Subscriptions) != 1 {
+		t.Errorf("Got %+v and %v, want the new subscription", r.cfg.Subscriptions, r.err)
+	}
+
+	err = os.WriteFile(DefaultPath, []byte("version: 1\nui:\n  view: tiles\n"), 0o644)
+	if err != nil {
+		t.Fatalf("Got an unexpected error: %q", err)
+	}
+	r = next()
+	if r.err == nil {
+		t.Error("Got no error for the invalid view")
+	}
+}
@@ -0,0 +1,59 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+
+	"github.com/aaronzipp/deeptube/sqlite"
+)
+
+// Migrate applies all migrations newer than the schema version stored in the
+// database's user_version.
+func Migrate(ctx context.Context, db *sql.DB) error {
+	migrations, err := fs.ReadDir(sqlite.Migrations, "migrations")
+	if err != nil {
+		return err
+	}
+	return migrate(ctx, db, migrations)
+}
+
+// migrate applies the given migrations, the first ones of the embedded ones,
+// that are newer than the schema version.
+func migrate(ctx context.Context, db *sql.DB, migrations []fs.DirEntry) error {
+	var version int
+	err := db.QueryRowContext(ctx, "PRAGMA user_version").Scan(&version)
+	if err != nil {
+		return err
+	}
+
+	for i := version; i < len(migrations); i++ {
+		name := migrations[i].Name()
+		statements, err := fs.ReadFile(sqlite.Migrations, "migrations/"+name)
+		if err != nil {
+			return err
+		}
+
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, string(statements))
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed applying migration %s: %w", name, err)
+		}
+		_, err = tx.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d", i+1))
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		err = tx.Commit()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"io/fs"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/aaronzipp/deeptube/sqlite"
+)
+
+func TestMigratePlaylistItems(t *testing.T) {
+	ctx := context.Background()
+	db, err := sql.Open("sqlite", "file:"+filepath.Join(t.TempDir(), Path)+"?_pragma=foreign_keys(1)")
+	if err != nil {
+		t.Fatalf("Got an unexpected error: %q", err)
+	}
+	defer db.Close()
+
+	migrations, err := fs.ReadDir(sqlite.Migrations, "migrations")
+	if err != nil {
+		t.Fatalf("Got an unexpected error: %q", err)
+	}
+	// 0006 added playlist_items, which 0007 moves into video_sources.
+	err = migrate(ctx, db, migrations[:6])
+	if err != nil {
+		t.Fatalf("Got an unexpected error: %q", err)
+	}
+	_, err = db.ExecContext(ctx, `
+insert into videos (video_id, title, was_live, is_hidden, fetched_at) values
+	('aaaaaaaaaaa', 'Upload', 0, 0, '2024-01-02 00:00:00'),
+	('bbbbbbbbbbb', 'Stream', 1, 0, null);
+insert into playlist_items (playlist_id, video_id, added_at) values
+	('PLa', 'aaaaaaaaaaa', '2024-01-01 10:00:00'),
+	('PLa', 'bbbbbbbbbbb', '2024-01-03 00:00:00');
+`)
+	if err != nil {
+		t.Fatalf("Got an unexpected error: %q", err)
+	}
+
+	err = migrate(ctx, db, migrations)
+	if err != nil {
+		t.Fatalf("Got an unexpected error: %q", err)
+	}
+
+	got, err := New(db).FetchVideoSources(ctx, "aaaaaaaaaaa")
+	if err != nil {
+		t.Fatalf("Got an unexpected error: %q", err)
+	}
+	streamSources, err := New(db).FetchVideoSources(ctx, "bbbbbbbbbbb")
+	if err != nil {
+		t.Fatalf("Got an unexpected error: %q", err)
+	}
+	got = append(got, streamSources...)
+	want := []VideoSource{
+		{
+			VideoID:   "aaaaaaaaaaa",
+			FeedID:    "PLa",
+			FeedType:  "playlist",
+			FirstSeen: "2024-01-02 00:00:00",
+			AddedAt:   sql.NullString{String: "2024-01-01 10:00:00", Valid: true},
+		},
+		{
+			VideoID:   "bbbbbbbbbbb",
+			FeedID:    "PLa",
+			FeedType:  "playlist",
+			FirstSeen: "2024-01-03 00:00:00",
+			AddedAt:   sql.NullString{String: "2024-01-03 00:00:00", Valid: true},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %+v, want %+v", got, want)
+	}
+
+	// 0008 sets the type of the moved videos as well.
+	rows, err := db.QueryContext(ctx, "select video_type from videos order by video_id")
+	if err != nil {
+		t.Fatalf("Got an unexpected error: %q", err)
+	}
+	defer rows.Close()
+	var types []string
+	for rows.Next() {
+		var videoType string
+		err = rows.Scan(&videoType)
+		if err != nil {
+			t.Fatalf("Got an unexpected error: %q", err)
+		}
+		types = append(types, videoType)
+	}
+	if want := []string{"normal", "live"}; !reflect.DeepEqual(types, want) {
+		t.Errorf("Got types %v, want %v", types, want)
+	}
+}
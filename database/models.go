@@ -8,6 +8,26 @@ import (
 	"database/sql"
 )
 
+type AppState struct {
+	Key   string
+	Value string
+}
+
+type ArchivedVideo struct {
+	VideoID      string
+	Title        sql.NullString
+	ThumbnailUrl sql.NullString
+	ChannelName  sql.NullString
+	Description  sql.NullString
+	PublishedAt  sql.NullString
+	Hours        sql.NullInt64
+	Minutes      sql.NullInt64
+	Seconds      sql.NullInt64
+	WasLive      sql.NullInt64
+	IsHidden     sql.NullInt64
+	ArchivedAt   sql.NullString
+}
+
 type Thumbnail struct {
 	VideoID   string
 	Thumbnail []byte
@@ -26,4 +46,19 @@ type Video struct {
 	Seconds      sql.NullInt64
 	WasLive      sql.NullInt64
 	IsHidden     sql.NullInt64
+	WatchedAt    sql.NullString
+	QueuedAt     sql.NullString
+	Categories   sql.NullString
+	Position     sql.NullInt64
+	FetchedAt    sql.NullString
+	VideoType    string
+}
+
+type VideoSource struct {
+	VideoID   string
+	FeedID    string
+	FeedType  string
+	FeedName  string
+	FirstSeen string
+	AddedAt   sql.NullString
 }
@@ -0,0 +1,70 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	_ "modernc.org/sqlite"
+)
+
+const Path = "videos.db"
+
+// Foreign keys are off by default in SQLite, without them deleting a video
+// would leave its thumbnail behind.
+const dsn = "file:" + Path + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+
+var (
+	openOnce sync.Once
+	shared   *sql.DB
+	openErr  error
+)
+
+// Open returns the database handle shared by the whole application, creating
+// and migrating the database on first use. It must not be closed by callers.
+func Open() (*sql.DB, error) {
+	openOnce.Do(func() {
+		shared, openErr = sql.Open("sqlite", dsn)
+		if openErr != nil {
+			return
+		}
+		// SQLite only allows a single writer, funnelling everything through one
+		// connection avoids "database is locked" errors between goroutines.
+		shared.SetMaxOpenConns(1)
+
+		openErr = Migrate(context.Background(), shared)
+	})
+	return shared, openErr
+}
+
+// Vacuum rebuilds the database file and returns the number of bytes reclaimed.
+func Vacuum(ctx context.Context, db *sql.DB) (int64, error) {
+	before, err := size(ctx, db)
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = db.ExecContext(ctx, "VACUUM")
+	if err != nil {
+		return 0, err
+	}
+
+	after, err := size(ctx, db)
+	if err != nil {
+		return 0, err
+	}
+	return before - after, nil
+}
+
+func size(ctx context.Context, db *sql.DB) (int64, error) {
+	var pageCount, pageSize int64
+	err := db.QueryRowContext(ctx, "PRAGMA page_count").Scan(&pageCount)
+	if err != nil {
+		return 0, err
+	}
+	err = db.QueryRowContext(ctx, "PRAGMA page_size").Scan(&pageSize)
+	if err != nil {
+		return 0, err
+	}
+	return pageCount * pageSize, nil
+}
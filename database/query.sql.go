@@ -10,6 +10,44 @@ import (
 	"database/sql"
 )
 
+const addPlaceholderVideo = `-- name: AddPlaceholderVideo :execrows
+insert into videos (video_id, title, thumbnail_url, channel_name, description, published_at, hours, minutes, seconds, was_live, categories, is_hidden, fetched_at, video_type)
+values (?, ?, ?, ?, '', ?, ?, ?, ?, 0, '', 0, ?, ?)
+on conflict(video_id) do nothing
+`
+
+type AddPlaceholderVideoParams struct {
+	VideoID      string
+	Title        sql.NullString
+	ThumbnailUrl sql.NullString
+	ChannelName  sql.NullString
+	PublishedAt  sql.NullString
+	Hours        sql.NullInt64
+	Minutes      sql.NullInt64
+	Seconds      sql.NullInt64
+	FetchedAt    sql.NullString
+	VideoType    string
+}
+
+func (q *Queries) AddPlaceholderVideo(ctx context.Context, arg AddPlaceholderVideoParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, addPlaceholderVideo,
+		arg.VideoID,
+		arg.Title,
+		arg.ThumbnailUrl,
+		arg.ChannelName,
+		arg.PublishedAt,
+		arg.Hours,
+		arg.Minutes,
+		arg.Seconds,
+		arg.FetchedAt,
+		arg.VideoType,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const addThumbnail = `-- name: AddThumbnail :exec
 INSERT INTO thumbnails(video_id, thumbnail, updated_at)
 VALUES (?, ?, CURRENT_TIMESTAMP)
@@ -29,8 +67,8 @@ func (q *Queries) AddThumbnail(ctx context.Context, arg AddThumbnailParams) erro
 }
 
 const addVideo = `-- name: AddVideo :exec
-INSERT INTO videos (video_id, title, thumbnail_url, channel_name, description, published_at, hours, minutes, seconds, was_live, is_hidden)
-    VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 0)
+INSERT INTO videos (video_id, title, thumbnail_url, channel_name, description, published_at, hours, minutes, seconds, was_live, categories, video_type, is_hidden, fetched_at)
+    VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 0, CURRENT_TIMESTAMP)
     ON CONFLICT(video_id) DO UPDATE SET
 	title = excluded.title,
 	thumbnail_url = excluded.thumbnail_url,
@@ -40,7 +78,9 @@ INSERT INTO videos (video_id, title, thumbnail_url, channel_name, description, p
 	hours = excluded.hours,
 	minutes = excluded.minutes,
 	seconds = excluded.seconds,
-	was_live = excluded.was_live
+	was_live = excluded.was_live,
+	categories = excluded.categories,
+	video_type = case videos.video_type when 'short' then 'short' else excluded.video_type end
 `
 
 type AddVideoParams struct {
@@ -54,6 +94,8 @@ type AddVideoParams struct {
 	Minutes      sql.NullInt64
 	Seconds      sql.NullInt64
 	WasLive      sql.NullInt64
+	Categories   sql.NullString
+	VideoType    string
 }
 
 func (q *Queries) AddVideo(ctx context.Context, arg AddVideoParams) error {
@@ -68,10 +110,410 @@ func (q *Queries) AddVideo(ctx context.Context, arg AddVideoParams) error {
 		arg.Minutes,
 		arg.Seconds,
 		arg.WasLive,
+		arg.Categories,
+		arg.VideoType,
+	)
+	return err
+}
+
+const addVideoSource = `-- name: AddVideoSource :exec
+insert into video_sources (video_id, feed_id, feed_type, feed_name, first_seen, added_at)
+values (?, ?, ?, ?, CURRENT_TIMESTAMP, ?)
+on conflict(video_id, feed_id, feed_type) do update set
+	feed_name = excluded.feed_name,
+	added_at = excluded.added_at
+`
+
+type AddVideoSourceParams struct {
+	VideoID  string
+	FeedID   string
+	FeedType string
+	FeedName string
+	AddedAt  sql.NullString
+}
+
+func (q *Queries) AddVideoSource(ctx context.Context, arg AddVideoSourceParams) error {
+	_, err := q.db.ExecContext(ctx, addVideoSource,
+		arg.VideoID,
+		arg.FeedID,
+		arg.FeedType,
+		arg.FeedName,
+		arg.AddedAt,
 	)
 	return err
 }
 
+const archiveExpiredVideos = `-- name: ArchiveExpiredVideos :execrows
+insert into archived_videos (video_id, title, thumbnail_url, channel_name, description, published_at, hours, minutes, seconds, was_live, is_hidden, archived_at)
+select video_id, title, thumbnail_url, channel_name, description, published_at, hours, minutes, seconds, was_live, is_hidden, CURRENT_TIMESTAMP
+from videos
+where published_at < ?1
+and fetched_at < ?1
+and watched_at is null
+and queued_at is null
+on conflict(video_id) do nothing
+`
+
+func (q *Queries) ArchiveExpiredVideos(ctx context.Context, publishedAt sql.NullString) (int64, error) {
+	result, err := q.db.ExecContext(ctx, archiveExpiredVideos, publishedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const countVideosSince = `-- name: CountVideosSince :one
+select count(*) from videos
+where is_hidden = 0
+and (published_at > ?1 or fetched_at > ?1)
+and (cast(?2 as integer) = 0 or video_type != 'short')
+`
+
+type CountVideosSinceParams struct {
+	Since      sql.NullString
+	HideShorts int64
+}
+
+func (q *Queries) CountVideosSince(ctx context.Context, arg CountVideosSinceParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countVideosSince, arg.Since, arg.HideShorts)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const deleteExpiredVideos = `-- name: DeleteExpiredVideos :execrows
+delete from videos
+where published_at < ?1
+and fetched_at < ?1
+and watched_at is null
+and queued_at is null
+`
+
+func (q *Queries) DeleteExpiredVideos(ctx context.Context, publishedAt sql.NullString) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteExpiredVideos, publishedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteHiddenThumbnails = `-- name: DeleteHiddenThumbnails :execrows
+delete from thumbnails
+where video_id in (select video_id from videos where is_hidden = 1)
+`
+
+func (q *Queries) DeleteHiddenThumbnails(ctx context.Context) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteHiddenThumbnails)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteThumbnail = `-- name: DeleteThumbnail :exec
+delete from thumbnails where video_id = ?
+`
+
+func (q *Queries) DeleteThumbnail(ctx context.Context, videoID string) error {
+	_, err := q.db.ExecContext(ctx, deleteThumbnail, videoID)
+	return err
+}
+
+const deleteThumbnailsPublishedBefore = `-- name: DeleteThumbnailsPublishedBefore :execrows
+delete from thumbnails
+where video_id in (select video_id from videos where published_at < ?1 and fetched_at < ?1)
+`
+
+func (q *Queries) DeleteThumbnailsPublishedBefore(ctx context.Context, publishedAt sql.NullString) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteThumbnailsPublishedBefore, publishedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const fetchCategories = `-- name: FetchCategories :many
+select distinct categories from videos
+where is_hidden = 0
+and categories != ''
+`
+
+func (q *Queries) FetchCategories(ctx context.Context) ([]sql.NullString, error) {
+	rows, err := q.db.QueryContext(ctx, fetchCategories)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []sql.NullString
+	for rows.Next() {
+		var categories sql.NullString
+		if err := rows.Scan(&categories); err != nil {
+			return nil, err
+		}
+		items = append(items, categories)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const fetchFeeds = `-- name: FetchFeeds :many
+select distinct video_sources.feed_id, video_sources.feed_name
+from video_sources
+join videos on videos.video_id = video_sources.video_id
+where videos.is_hidden = 0
+and video_sources.feed_name != ''
+order by video_sources.feed_name
+`
+
+type FetchFeedsRow struct {
+	FeedID   string
+	FeedName string
+}
+
+func (q *Queries) FetchFeeds(ctx context.Context) ([]FetchFeedsRow, error) {
+	rows, err := q.db.QueryContext(ctx, fetchFeeds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FetchFeedsRow
+	for rows.Next() {
+		var i FetchFeedsRow
+		if err := rows.Scan(&i.FeedID, &i.FeedName); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const fetchHistory = `-- name: FetchHistory :many
+select video_id, title, thumbnail_url, channel_name, description, published_at, hours, minutes, seconds, was_live, is_hidden, watched_at, queued_at, categories, position, fetched_at, video_type
+from videos
+where published_at >= ?1
+and published_at < ?2
+and (cast(?3 as text) = '' or instr(',' || categories || ',', ',' || ?3 || ',') > 0)
+order by published_at
+`
+
+type FetchHistoryParams struct {
+	PublishedFrom  sql.NullString
+	PublishedUntil sql.NullString
+	Category       string
+}
+
+func (q *Queries) FetchHistory(ctx context.Context, arg FetchHistoryParams) ([]Video, error) {
+	rows, err := q.db.QueryContext(ctx, fetchHistory, arg.PublishedFrom, arg.PublishedUntil, arg.Category)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Video
+	for rows.Next() {
+		var i Video
+		if err := rows.Scan(
+			&i.VideoID,
+			&i.Title,
+			&i.ThumbnailUrl,
+			&i.ChannelName,
+			&i.Description,
+			&i.PublishedAt,
+			&i.Hours,
+			&i.Minutes,
+			&i.Seconds,
+			&i.WasLive,
+			&i.IsHidden,
+			&i.WatchedAt,
+			&i.QueuedAt,
+			&i.Categories,
+			&i.Position,
+			&i.FetchedAt,
+			&i.VideoType,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const fetchPlaylistItems = `-- name: FetchPlaylistItems :many
+select video_id, added_at
+from video_sources
+where feed_id = ?1
+and feed_type = 'playlist'
+order by added_at desc
+limit ?2
+`
+
+type FetchPlaylistItemsParams struct {
+	FeedID   string
+	MaxItems int64
+}
+
+type FetchPlaylistItemsRow struct {
+	VideoID string
+	AddedAt sql.NullString
+}
+
+func (q *Queries) FetchPlaylistItems(ctx context.Context, arg FetchPlaylistItemsParams) ([]FetchPlaylistItemsRow, error) {
+	rows, err := q.db.QueryContext(ctx, fetchPlaylistItems, arg.FeedID, arg.MaxItems)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FetchPlaylistItemsRow
+	for rows.Next() {
+		var i FetchPlaylistItemsRow
+		if err := rows.Scan(&i.VideoID, &i.AddedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const fetchPlaylistVideos = `-- name: FetchPlaylistVideos :many
+select videos.video_id, videos.title, videos.thumbnail_url, videos.channel_name, videos.description, videos.published_at, videos.hours, videos.minutes, videos.seconds, videos.was_live, videos.is_hidden, videos.watched_at, videos.queued_at, videos.categories, videos.position, videos.fetched_at, videos.video_type, video_sources.added_at
+from videos
+join video_sources on video_sources.video_id = videos.video_id
+where video_sources.feed_id = ?
+and video_sources.feed_type = 'playlist'
+and videos.is_hidden = 0
+order by video_sources.added_at desc
+`
+
+type FetchPlaylistVideosRow struct {
+	Video   Video
+	AddedAt sql.NullString
+}
+
+func (q *Queries) FetchPlaylistVideos(ctx context.Context, feedID string) ([]FetchPlaylistVideosRow, error) {
+	rows, err := q.db.QueryContext(ctx, fetchPlaylistVideos, feedID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FetchPlaylistVideosRow
+	for rows.Next() {
+		var i FetchPlaylistVideosRow
+		if err := rows.Scan(
+			&i.Video.VideoID,
+			&i.Video.Title,
+			&i.Video.ThumbnailUrl,
+			&i.Video.ChannelName,
+			&i.Video.Description,
+			&i.Video.PublishedAt,
+			&i.Video.Hours,
+			&i.Video.Minutes,
+			&i.Video.Seconds,
+			&i.Video.WasLive,
+			&i.Video.IsHidden,
+			&i.Video.WatchedAt,
+			&i.Video.QueuedAt,
+			&i.Video.Categories,
+			&i.Video.Position,
+			&i.Video.FetchedAt,
+			&i.Video.VideoType,
+			&i.AddedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const fetchQueuedVideos = `-- name: FetchQueuedVideos :many
+select video_id, title, thumbnail_url, channel_name, description, published_at, hours, minutes, seconds, was_live, is_hidden, watched_at, queued_at, categories, position, fetched_at, video_type
+from videos
+where queued_at is not null
+and is_hidden = 0
+order by queued_at
+`
+
+func (q *Queries) FetchQueuedVideos(ctx context.Context) ([]Video, error) {
+	rows, err := q.db.QueryContext(ctx, fetchQueuedVideos)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Video
+	for rows.Next() {
+		var i Video
+		if err := rows.Scan(
+			&i.VideoID,
+			&i.Title,
+			&i.ThumbnailUrl,
+			&i.ChannelName,
+			&i.Description,
+			&i.PublishedAt,
+			&i.Hours,
+			&i.Minutes,
+			&i.Seconds,
+			&i.WasLive,
+			&i.IsHidden,
+			&i.WatchedAt,
+			&i.QueuedAt,
+			&i.Categories,
+			&i.Position,
+			&i.FetchedAt,
+			&i.VideoType,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const fetchState = `-- name: FetchState :one
+select value from app_state
+where key = ?
+`
+
+func (q *Queries) FetchState(ctx context.Context, key string) (string, error) {
+	row := q.db.QueryRowContext(ctx, fetchState, key)
+	var value string
+	err := row.Scan(&value)
+	return value, err
+}
+
 const fetchThumbnail = `-- name: FetchThumbnail :one
 SELECT thumbnail FROM thumbnails WHERE video_id = ?
 `
@@ -83,8 +525,54 @@ func (q *Queries) FetchThumbnail(ctx context.Context, videoID string) ([]byte, e
 	return thumbnail, err
 }
 
+const fetchThumbnailSizes = `-- name: FetchThumbnailSizes :many
+select t.video_id, length(t.thumbnail) as size
+from thumbnails t
+join videos v on v.video_id = t.video_id
+order by v.published_at desc
+`
+
+type FetchThumbnailSizesRow struct {
+	VideoID string
+	Size    int64
+}
+
+func (q *Queries) FetchThumbnailSizes(ctx context.Context) ([]FetchThumbnailSizesRow, error) {
+	rows, err := q.db.QueryContext(ctx, fetchThumbnailSizes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FetchThumbnailSizesRow
+	for rows.Next() {
+		var i FetchThumbnailSizesRow
+		if err := rows.Scan(&i.VideoID, &i.Size); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const fetchVideoCategories = `-- name: FetchVideoCategories :one
+select categories from videos where video_id = ?
+`
+
+func (q *Queries) FetchVideoCategories(ctx context.Context, videoID string) (sql.NullString, error) {
+	row := q.db.QueryRowContext(ctx, fetchVideoCategories, videoID)
+	var categories sql.NullString
+	err := row.Scan(&categories)
+	return categories, err
+}
+
 const fetchVideos = `-- name: FetchVideos :many
-select video_id, title, thumbnail_url, channel_name, description, published_at, hours, minutes, seconds, was_live, is_hidden
+select video_id, title, thumbnail_url, channel_name, description, published_at, hours, minutes, seconds, was_live, is_hidden, watched_at, queued_at, categories, position, fetched_at, video_type
 from videos
 where is_hidden = 0
 order by published_at desc
@@ -116,6 +604,173 @@ func (q *Queries) FetchVideos(ctx context.Context, arg FetchVideosParams) ([]Vid
 			&i.Seconds,
 			&i.WasLive,
 			&i.IsHidden,
+			&i.WatchedAt,
+			&i.QueuedAt,
+			&i.Categories,
+			&i.Position,
+			&i.FetchedAt,
+			&i.VideoType,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const fetchVideoSources = `-- name: FetchVideoSources :many
+select video_id, feed_id, feed_type, feed_name, first_seen, added_at
+from video_sources
+where video_id = ?
+order by first_seen
+`
+
+func (q *Queries) FetchVideoSources(ctx context.Context, videoID string) ([]VideoSource, error) {
+	rows, err := q.db.QueryContext(ctx, fetchVideoSources, videoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []VideoSource
+	for rows.Next() {
+		var i VideoSource
+		if err := rows.Scan(
+			&i.VideoID,
+			&i.FeedID,
+			&i.FeedType,
+			&i.FeedName,
+			&i.FirstSeen,
+			&i.AddedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const fetchVideosForThumbnails = `-- name: FetchVideosForThumbnails :many
+select v.video_id, v.thumbnail_url, cast(coalesce(length(t.thumbnail), 0) as integer) as size
+from videos v
+left join thumbnails t on t.video_id = v.video_id
+where v.is_hidden = 0
+and (v.published_at >= ?1 or v.fetched_at >= ?1)
+order by v.published_at desc
+`
+
+type FetchVideosForThumbnailsRow struct {
+	VideoID      string
+	ThumbnailUrl sql.NullString
+	Size         int64
+}
+
+func (q *Queries) FetchVideosForThumbnails(ctx context.Context, publishedAt sql.NullString) ([]FetchVideosForThumbnailsRow, error) {
+	rows, err := q.db.QueryContext(ctx, fetchVideosForThumbnails, publishedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FetchVideosForThumbnailsRow
+	for rows.Next() {
+		var i FetchVideosForThumbnailsRow
+		if err := rows.Scan(&i.VideoID, &i.ThumbnailUrl, &i.Size); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const filterVideos = `-- name: FilterVideos :many
+select video_id, title, thumbnail_url, channel_name, description, published_at, hours, minutes, seconds, was_live, is_hidden, watched_at, queued_at, categories, position, fetched_at, video_type
+from videos
+where is_hidden = 0
+and (title like ?1 escape '\' or channel_name like ?1 escape '\')
+and (cast(?2 as text) = '' or instr(',' || categories || ',', ',' || ?2 || ',') > 0)
+and (cast(?3 as integer) = 0 or watched_at is null)
+and (cast(?4 as integer) = 0 or was_live = 0)
+and (cast(?5 as text) = '' or exists (
+	select 1 from video_sources
+	where video_sources.video_id = videos.video_id
+	and video_sources.feed_id = ?5
+))
+and (cast(?6 as text) = '' or video_type = ?6)
+and (cast(?7 as integer) = 0 or video_type != 'short')
+order by
+	case cast(?8 as text) when 'oldest' then published_at end,
+	case ?8 when 'longest' then hours * 3600 + minutes * 60 + seconds end desc,
+	case ?8 when 'shortest' then hours * 3600 + minutes * 60 + seconds end,
+	published_at desc
+limit ?9
+`
+
+type FilterVideosParams struct {
+	Pattern    sql.NullString
+	Category   string
+	Unwatched  int64
+	HideLive   int64
+	Source     string
+	VideoType  string
+	HideShorts int64
+	Sort       string
+	MaxVideos  int64
+}
+
+func (q *Queries) FilterVideos(ctx context.Context, arg FilterVideosParams) ([]Video, error) {
+	rows, err := q.db.QueryContext(ctx, filterVideos,
+		arg.Pattern,
+		arg.Category,
+		arg.Unwatched,
+		arg.HideLive,
+		arg.Source,
+		arg.VideoType,
+		arg.HideShorts,
+		arg.Sort,
+		arg.MaxVideos,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Video
+	for rows.Next() {
+		var i Video
+		if err := rows.Scan(
+			&i.VideoID,
+			&i.Title,
+			&i.ThumbnailUrl,
+			&i.ChannelName,
+			&i.Description,
+			&i.PublishedAt,
+			&i.Hours,
+			&i.Minutes,
+			&i.Seconds,
+			&i.WasLive,
+			&i.IsHidden,
+			&i.WatchedAt,
+			&i.QueuedAt,
+			&i.Categories,
+			&i.Position,
+			&i.FetchedAt,
+			&i.VideoType,
 		); err != nil {
 			return nil, err
 		}
@@ -141,3 +796,174 @@ func (q *Queries) HideVideo(ctx context.Context, videoID string) error {
 	_, err := q.db.ExecContext(ctx, hideVideo, videoID)
 	return err
 }
+
+const hideVideosPublishedBefore = `-- name: HideVideosPublishedBefore :execrows
+update videos
+set is_hidden = 1
+where is_hidden = 0
+and queued_at is null
+and published_at < ?
+`
+
+func (q *Queries) HideVideosPublishedBefore(ctx context.Context, publishedAt sql.NullString) (int64, error) {
+	result, err := q.db.ExecContext(ctx, hideVideosPublishedBefore, publishedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const isVideoArchived = `-- name: IsVideoArchived :one
+select exists(select 1 from archived_videos where video_id = ?)
+`
+
+func (q *Queries) IsVideoArchived(ctx context.Context, videoID string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, isVideoArchived, videoID)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const isVideoStored = `-- name: IsVideoStored :one
+select exists(select 1 from videos where video_id = ?)
+`
+
+func (q *Queries) IsVideoStored(ctx context.Context, videoID string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, isVideoStored, videoID)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const markVideoUnwatched = `-- name: MarkVideoUnwatched :exec
+update videos
+set watched_at = null
+where video_id = ?
+`
+
+func (q *Queries) MarkVideoUnwatched(ctx context.Context, videoID string) error {
+	_, err := q.db.ExecContext(ctx, markVideoUnwatched, videoID)
+	return err
+}
+
+const markVideoWatched = `-- name: MarkVideoWatched :exec
+update videos
+set watched_at = CURRENT_TIMESTAMP
+where video_id = ?
+`
+
+func (q *Queries) MarkVideoWatched(ctx context.Context, videoID string) error {
+	_, err := q.db.ExecContext(ctx, markVideoWatched, videoID)
+	return err
+}
+
+const markVideoWatchedAt = `-- name: MarkVideoWatchedAt :execrows
+update videos
+set watched_at = ?
+where video_id = ?
+and watched_at is null
+`
+
+type MarkVideoWatchedAtParams struct {
+	WatchedAt sql.NullString
+	VideoID   string
+}
+
+func (q *Queries) MarkVideoWatchedAt(ctx context.Context, arg MarkVideoWatchedAtParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, markVideoWatchedAt, arg.WatchedAt, arg.VideoID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const queueVideo = `-- name: QueueVideo :exec
+update videos
+set queued_at = coalesce(queued_at, CURRENT_TIMESTAMP)
+where video_id = ?
+`
+
+func (q *Queries) QueueVideo(ctx context.Context, videoID string) error {
+	_, err := q.db.ExecContext(ctx, queueVideo, videoID)
+	return err
+}
+
+const queueVideoAt = `-- name: QueueVideoAt :execrows
+update videos
+set queued_at = ?
+where video_id = ?
+and queued_at is null
+`
+
+type QueueVideoAtParams struct {
+	QueuedAt sql.NullString
+	VideoID  string
+}
+
+func (q *Queries) QueueVideoAt(ctx context.Context, arg QueueVideoAtParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, queueVideoAt, arg.QueuedAt, arg.VideoID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const renameFeed = `-- name: RenameFeed :exec
+update video_sources
+set feed_name = ?1
+where feed_id = ?2
+and feed_name != ?1
+`
+
+type RenameFeedParams struct {
+	FeedName string
+	FeedID   string
+}
+
+func (q *Queries) RenameFeed(ctx context.Context, arg RenameFeedParams) error {
+	_, err := q.db.ExecContext(ctx, renameFeed, arg.FeedName, arg.FeedID)
+	return err
+}
+
+const setState = `-- name: SetState :exec
+insert into app_state (key, value)
+values (?, ?)
+on conflict(key) do update set value = excluded.value
+`
+
+type SetStateParams struct {
+	Key   string
+	Value string
+}
+
+func (q *Queries) SetState(ctx context.Context, arg SetStateParams) error {
+	_, err := q.db.ExecContext(ctx, setState, arg.Key, arg.Value)
+	return err
+}
+
+const setVideoPosition = `-- name: SetVideoPosition :exec
+update videos
+set position = ?
+where video_id = ?
+`
+
+type SetVideoPositionParams struct {
+	Position sql.NullInt64
+	VideoID  string
+}
+
+func (q *Queries) SetVideoPosition(ctx context.Context, arg SetVideoPositionParams) error {
+	_, err := q.db.ExecContext(ctx, setVideoPosition, arg.Position, arg.VideoID)
+	return err
+}
+
+const unqueueVideo = `-- name: UnqueueVideo :exec
+update videos
+set queued_at = null
+where video_id = ?
+`
+
+func (q *Queries) UnqueueVideo(ctx context.Context, videoID string) error {
+	_, err := q.db.ExecContext(ctx, unqueueVideo, videoID)
+	return err
+}
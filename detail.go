@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+
+	"github.com/aaronzipp/deeptube/video"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+var linkPattern = regexp.MustCompile(`https?://[^\s<>"]+[^\s<>".,;:!?)\]]`)
+
+// descriptionSegments splits a description into text and clickable links.
+func descriptionSegments(description string) []widget.RichTextSegment {
+	var segments []widget.RichTextSegment
+	appendText := func(text string) {
+		if text != "" {
+			segments = append(segments, &widget.TextSegment{
+				Text:  text,
+				Style: widget.RichTextStyleInline,
+			})
+		}
+	}
+
+	last := 0
+	for _, match := range linkPattern.FindAllStringIndex(description, -1) {
+		link, err := url.Parse(description[match[0]:match[1]])
+		if err != nil {
+			continue
+		}
+		appendText(description[last:match[0]])
+		segments = append(segments, &widget.HyperlinkSegment{
+			Text: description[match[0]:match[1]],
+			URL:  link,
+		})
+		last = match[1]
+	}
+	appendText(description[last:])
+
+	return segments
+}
+
+// showDetail shows the full information of the card's video.
+func showDetail(c *videoCard) {
+	w := c.feed.window
+	vid := &c.video
+	var detail *dialog.CustomDialog
+
+	title := widget.NewLabelWithStyle(vid.Title, fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	title.Wrapping = fyne.TextWrapWord
+
+	channel := widget.NewLabelWithStyle(vid.ChannelName, fyne.TextAlignLeading, fyne.TextStyle{Italic: true})
+
+	published := widget.NewLabel(fmt.Sprintf(
+		"Published %s (%s)",
+		vid.PublishedAt.Local().Format("Monday, 2 January 2006 15:04"),
+		vid.TimeSincePublished(),
+	))
+	duration := widget.NewLabel("Duration " + vid.VideoLength.Timestamp())
+
+	watchBtn := widget.NewButtonWithIcon("Watch", theme.MediaPlayIcon(), c.watch)
+
+	queueBtn := widget.NewButtonWithIcon("", theme.ContentAddIcon(), nil)
+	updateQueueBtn := func() {
+		if vid.Queued {
+			queueBtn.SetText("Remove from queue")
+			queueBtn.SetIcon(theme.ContentRemoveIcon())
+		} else {
+			queueBtn.SetText("Queue")
+			queueBtn.SetIcon(theme.ContentAddIcon())
+		}
+	}
+	updateQueueBtn()
+	queueBtn.OnTapped = func() {
+		c.toggleQueue()
+		updateQueueBtn()
+	}
+
+	hideBtn := widget.NewButtonWithIcon("Hide", theme.DeleteIcon(), func() {
+		detail.Hide()
+		c.hide()
+	})
+
+	info := container.NewVBox(
+		title,
+		channel,
+		published,
+		duration,
+		container.NewHBox(watchBtn, queueBtn, hideBtn),
+	)
+
+	chapters := container.NewVBox()
+	for _, chapter := range video.ParseChapters(vid.Description) {
+		start := video.LengthFromSeconds(chapter.Start).Timestamp()
+		chapterBtn := widget.NewButton(start+"  "+chapter.Title, func() {
+			err := videoPlayer.Open(*vid, chapter.Start)
+			if err != nil {
+				dialog.ShowError(err, w)
+			}
+		})
+		chapterBtn.Alignment = widget.ButtonAlignLeading
+		chapterBtn.Importance = widget.LowImportance
+		chapters.Add(chapterBtn)
+	}
+
+	description := widget.NewRichText(descriptionSegments(vid.Description)...)
+	description.Wrapping = fyne.TextWrapWord
+
+	body := container.NewVBox(description)
+	if len(chapters.Objects) > 0 {
+		body = container.NewVBox(
+			widget.NewLabelWithStyle("Chapters", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+			chapters,
+			widget.NewSeparator(),
+			description,
+		)
+	}
+
+	content := container.NewBorder(info, nil, nil, nil, container.NewVScroll(body))
+	detail = dialog.NewCustom("Details", "Close", content, w)
+	detail.Resize(fyne.NewSize(700, 600))
+	detail.Show()
+}
@@ -0,0 +1,674 @@
+package main
+
+import (
+	"log"
+	"slices"
+	"time"
+
+	"github.com/aaronzipp/deeptube/config"
+	"github.com/aaronzipp/deeptube/video"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	feedView   = "Feed"
+	shortsView = "Shorts"
+	queueView  = "Queue"
+
+	allCategories = "All categories"
+	allFeeds      = "All feeds"
+)
+
+// Preference keys, the window is restored from them when it opens.
+const (
+	prefWindowWidth  = "window.width"
+	prefWindowHeight = "window.height"
+	prefView         = "view"
+	prefCompact      = "compact"
+	prefCategory     = "filter.category"
+	prefUnwatched    = "filter.unwatched"
+	prefNoLive       = "filter.no_live"
+	prefSource       = "filter.source"
+	prefOrder        = "order"
+	prefHideShorts   = "hide_shorts"
+	prefTheme        = "theme"
+	prefAccent       = "theme.accent"
+)
+
+type keyBinding struct {
+	keys        string
+	description string
+}
+
+var keyBindings = []keyBinding{
+	{"← ↓ ↑ → / h j k l", "Move the focus"},
+	{"Enter", "Watch the focused video"},
+	{"Space", "Select the focused video"},
+	{"c", "Select all videos from the channel of the focused video"},
+	{"i", "Show details of the focused video"},
+	{"x", "Hide the focused or selected videos"},
+	{"q", "Add to or remove from the queue, queue the selected videos"},
+	{"w", "Mark as watched or unwatched, mark the selected videos watched"},
+	{"Escape", "Leave the selection mode"},
+	{"/ or Ctrl+F", "Search"},
+	{"r or Ctrl+R", "Refresh videos"},
+	{"?", "Show this help"},
+}
+
+// feed is the window showing the videos of the selected view.
+type feed struct {
+	window fyne.Window
+	prefs  fyne.Preferences
+	// ui holds the defaults from the config, the preferences take precedence.
+	ui config.UI
+	// playlists have a view each, listing their videos by when they were added.
+	playlists  []config.Playlist
+	viewSelect *widget.Select
+	grid       *fyne.Container
+	layout     *cardGrid
+	// shortsLayout arranges the Shorts view with vertical thumbnails.
+	shortsLayout *cardGrid
+	// compact shows one video per row with small thumbnails instead of the grid.
+	compact bool
+	// hideShorts leaves out Shorts in every view, the Shorts view included.
+	hideShorts bool
+	scroll     *container.Scroll
+	search     *widget.Entry
+	view       string
+	filter     video.Filter
+	refresh    func()
+	// seen is called after the watermark moved, i.e. the number of new videos changed.
+	seen func()
+	// lastSeen is the watermark from before the window was opened, videos
+	// published or fetched after it are marked as new.
+	lastSeen time.Time
+	visible  bool
+
+	cards []*videoCard
+	// focused is the index of the card focused with the keyboard, -1 if none.
+	focused int
+
+	// selecting switches taps from opening a card to selecting it.
+	selecting      bool
+	selectionBar   *fyne.Container
+	selectionCount *widget.Label
+	// anchor is the index of the card last toggled, shift-clicks select from there.
+	anchor int
+}
+
+func newFeed(a fyne.App, cfg config.Config, refresh, seen func()) *feed {
+	prefs := a.Preferences()
+	f := &feed{
+		window:     a.NewWindow(applicationName),
+		prefs:      prefs,
+		ui:         cfg.UI,
+		playlists:  cfg.Playlists,
+		view:       prefs.StringWithFallback(prefView, feedView),
+		compact:    prefs.BoolWithFallback(prefCompact, cfg.UI.View == config.ViewList),
+		hideShorts: prefs.Bool(prefHideShorts),
+		filter: video.Filter{
+			Category:  prefs.String(prefCategory),
+			Unwatched: prefs.Bool(prefUnwatched),
+			NoLive:    prefs.Bool(prefNoLive),
+			Source:    prefs.String(prefSource),
+			Order:     video.Order(prefs.StringWithFallback(prefOrder, string(video.Newest))),
+		},
+		refresh: refresh,
+		seen:    seen,
+		focused: -1,
+		anchor:  -1,
+	}
+	f.window.SetIcon(a.Icon())
+	f.window.Resize(fyne.NewSize(
+		float32(prefs.FloatWithFallback(prefWindowWidth, 1200)),
+		float32(prefs.FloatWithFallback(prefWindowHeight, 800)),
+	))
+	f.window.SetCloseIntercept(func() {
+		size := f.window.Canvas().Size()
+		prefs.SetFloat(prefWindowWidth, float64(size.Width))
+		prefs.SetFloat(prefWindowHeight, float64(size.Height))
+		f.visible = false
+		f.window.Hide()
+	})
+
+	width := func() float32 {
+		return f.scroll.Size().Width
+	}
+	f.layout = newCardGrid(width)
+	f.shortsLayout = newShortsGrid(width)
+	f.grid = container.New(f.gridLayout())
+
+	if !slices.Contains(f.views(), f.view) {
+		f.view = feedView
+	}
+	f.viewSelect = widget.NewSelect(f.views(), nil)
+	f.viewSelect.Selected = f.view
+	f.viewSelect.OnChanged = func(view string) {
+		f.view = view
+		prefs.SetString(prefView, view)
+		f.load()
+		f.window.Canvas().Unfocus()
+	}
+
+	f.search = widget.NewEntry()
+	f.search.SetPlaceHolder("Search")
+	f.search.OnChanged = func(query string) {
+		f.filter.Query = query
+		f.load()
+	}
+	f.search.OnSubmitted = func(string) {
+		f.window.Canvas().Unfocus()
+	}
+
+	compactBtn := widget.NewButton("", nil)
+	compactBtn.OnTapped = func() {
+		f.setCompact(!f.compact)
+		updateCompactButton(compactBtn, f.compact)
+	}
+	updateCompactButton(compactBtn, f.compact)
+
+	seenBtn := widget.NewButtonWithIcon("Mark all seen", theme.VisibilityIcon(), f.markAllSeen)
+
+	themeBtn := widget.NewButtonWithIcon("", theme.ColorPaletteIcon(), f.showThemeDialog)
+
+	selectBtn := widget.NewButtonWithIcon("Select", theme.CheckButtonCheckedIcon(), func() {
+		f.setSelecting(!f.selecting)
+	})
+
+	f.scroll = container.NewVScroll(f.grid)
+	f.selectionBar = f.newSelectionBar()
+	toolbar := container.NewVBox(
+		container.NewBorder(nil, nil, f.viewSelect, container.NewHBox(compactBtn, seenBtn, selectBtn, themeBtn), f.search),
+		f.newFilterBar(),
+	)
+
+	f.window.SetContent(container.NewBorder(toolbar, f.selectionBar, nil, nil, f.scroll))
+	f.window.SetMainMenu(f.newMainMenu())
+	f.registerKeys()
+
+	a.Settings().AddListener(func(fyne.Settings) {
+		for _, card := range f.cards {
+			card.refreshColors()
+		}
+	})
+	return f
+}
+
+// visit shows the latest videos when the window is opened and moves the
+// watermark, the cards keep marking the videos new since the previous visit.
+func (f *feed) visit() {
+	lastSeen, err := video.LastSeen()
+	if err != nil {
+		log.Printf("failed loading last seen: %s", err)
+	}
+	f.lastSeen = lastSeen
+	f.setLastSeen(time.Now())
+	f.visible = true
+	f.load()
+}
+
+// newFilterBar creates the controls for the category, order and filters.
+func (f *feed) newFilterBar() fyne.CanvasObject {
+	categories, err := video.CategoriesFromDB()
+	if err != nil {
+		log.Printf("failed loading categories: %s", err)
+	}
+	categorySelect := widget.NewSelect(append([]string{allCategories}, categories...), nil)
+	categorySelect.Selected = allCategories
+	if slices.Contains(categories, f.filter.Category) {
+		categorySelect.Selected = f.filter.Category
+	} else {
+		f.filter.Category = ""
+	}
+	categorySelect.OnChanged = func(category string) {
+		if category == allCategories {
+			category = ""
+		}
+		f.filter.Category = category
+		f.prefs.SetString(prefCategory, category)
+		f.load()
+	}
+
+	feeds, err := video.FeedsFromDB()
+	if err != nil {
+		log.Printf("failed loading feeds: %s", err)
+	}
+	feedNames := []string{allFeeds}
+	feedSelect := widget.NewSelect(nil, nil)
+	feedSelect.Selected = allFeeds
+	for _, feed := range feeds {
+		feedNames = append(feedNames, feed.Name)
+		if feed.FeedId == f.filter.Source {
+			feedSelect.Selected = feed.Name
+		}
+	}
+	if feedSelect.Selected == allFeeds {
+		f.filter.Source = ""
+	}
+	feedSelect.Options = feedNames
+	feedSelect.OnChanged = func(name string) {
+		f.filter.Source = ""
+		for _, feed := range feeds {
+			if feed.Name == name {
+				f.filter.Source = feed.FeedId
+			}
+		}
+		f.prefs.SetString(prefSource, f.filter.Source)
+		f.load()
+	}
+
+	orders := make([]string, len(video.Orders))
+	for i, order := range video.Orders {
+		orders[i] = string(order)
+	}
+	orderSelect := widget.NewSelect(orders, nil)
+	orderSelect.Selected = string(f.filter.Order)
+	orderSelect.OnChanged = func(order string) {
+		f.filter.Order = video.Order(order)
+		f.prefs.SetString(prefOrder, order)
+		f.load()
+	}
+
+	unwatchedCheck := widget.NewCheck("Unwatched only", nil)
+	unwatchedCheck.Checked = f.filter.Unwatched
+	unwatchedCheck.OnChanged = func(checked bool) {
+		f.filter.Unwatched = checked
+		f.prefs.SetBool(prefUnwatched, checked)
+		f.load()
+	}
+
+	noLiveCheck := widget.NewCheck("No live streams", nil)
+	noLiveCheck.Checked = f.filter.NoLive
+	noLiveCheck.OnChanged = func(checked bool) {
+		f.filter.NoLive = checked
+		f.prefs.SetBool(prefNoLive, checked)
+		f.load()
+	}
+
+	return container.NewHBox(categorySelect, feedSelect, orderSelect, unwatchedCheck, noLiveCheck)
+}
+
+func updateCompactButton(button *widget.Button, compact bool) {
+	if compact {
+		button.SetText("Grid")
+		button.SetIcon(theme.GridIcon())
+	} else {
+		button.SetText("List")
+		button.SetIcon(theme.ListIcon())
+	}
+}
+
+// views are the names of the views to choose from.
+func (f *feed) views() []string {
+	views := []string{feedView}
+	if !f.hideShorts {
+		views = append(views, shortsView)
+	}
+	views = append(views, queueView)
+	for _, playlist := range f.playlists {
+		views = append(views, playlist.Playlist)
+	}
+	return views
+}
+
+// setPlaylists updates the playlist views after the config changed.
+func (f *feed) setPlaylists(playlists []config.Playlist) {
+	f.playlists = playlists
+	f.viewSelect.SetOptions(f.views())
+	if !slices.Contains(f.views(), f.view) {
+		f.viewSelect.SetSelected(feedView)
+	}
+}
+
+// playlist returns the playlist shown in the current view, if it is one.
+func (f *feed) playlist() (config.Playlist, bool) {
+	for _, playlist := range f.playlists {
+		if playlist.Playlist == f.view {
+			return playlist, true
+		}
+	}
+	return config.Playlist{}, false
+}
+
+// setHideShorts leaves out Shorts everywhere or shows them again.
+func (f *feed) setHideShorts(hide bool) {
+	f.hideShorts = hide
+	f.prefs.SetBool(prefHideShorts, hide)
+	f.viewSelect.SetOptions(f.views())
+	if f.view == shortsView && hide {
+		f.viewSelect.SetSelected(feedView)
+	} else {
+		f.load()
+	}
+	// The Shorts no longer count as new videos.
+	f.seen()
+}
+
+// videos returns the videos of the current view passing the filter. The
+// queue keeps the order videos were queued in, playlists list the videos
+// added last first. Shorts have a view of their own instead of the feed.
+func (f *feed) videos() (video.Videos, error) {
+	filter := f.filter
+	filter.NoShorts = f.hideShorts
+	switch f.view {
+	case feedView:
+		filter.NoShorts = true
+	case shortsView:
+		filter.Type = video.ShortVideo
+	case queueView:
+		videos, err := video.QueuedVideosFromDB()
+		if err != nil {
+			return nil, err
+		}
+		return videos.Filter(filter), nil
+	}
+	if playlist, ok := f.playlist(); ok {
+		videos, err := video.PlaylistVideosFromDB(playlist.ID)
+		if err != nil {
+			return nil, err
+		}
+		videos = videos.Filter(filter)
+		return videos[:min(len(videos), f.ui.Videos)], nil
+	}
+	return video.FilteredVideosFromDB(filter, f.ui.Videos)
+}
+
+// load shows the videos from the top, after the view or filters changed.
+func (f *feed) load() {
+	f.update()
+	f.scroll.ScrollToTop()
+}
+
+// reload shows the latest videos, keeping the scroll position, focus and selection.
+func (f *feed) reload() {
+	offset := f.scroll.Offset
+	focusedID := ""
+	if card := f.focusedCard(); card != nil {
+		focusedID = card.video.VideoId
+	}
+	selected := make(map[string]bool)
+	for _, card := range f.selectedCards() {
+		selected[card.video.VideoId] = true
+	}
+
+	f.update()
+
+	for i, card := range f.cards {
+		if card.video.VideoId == focusedID {
+			f.focused = i
+			card.setFocused(true)
+		}
+		card.setSelected(selected[card.video.VideoId])
+	}
+	f.updateSelectionCount()
+	f.scroll.ScrollToOffset(offset)
+}
+
+// update replaces the cards with the videos of the current view.
+func (f *feed) update() {
+	videos, err := f.videos()
+	if err != nil {
+		dialog.ShowError(err, f.window)
+		return
+	}
+
+	// The listeners and pending thumbnails belong to the replaced cards.
+	clear(progressListeners)
+	clear(pendingThumbnails)
+
+	f.cards = nil
+	f.focused = -1
+	f.anchor = -1
+	objects := make([]fyne.CanvasObject, len(videos))
+	for i, vid := range videos {
+		card := newVideoCard(f, vid)
+		f.cards = append(f.cards, card)
+		objects[i] = card.object
+	}
+	f.grid.Layout = f.gridLayout()
+	f.grid.Objects = objects
+	f.grid.Refresh()
+	f.updateSelectionCount()
+}
+
+func (f *feed) setCompact(compact bool) {
+	f.compact = compact
+	f.prefs.SetBool(prefCompact, compact)
+	f.load()
+}
+
+// cardGrid returns the grid arranging the cards of the current view.
+func (f *feed) cardGrid() *cardGrid {
+	if f.view == shortsView {
+		return f.shortsLayout
+	}
+	return f.layout
+}
+
+// gridLayout returns the layout of the current view and mode.
+func (f *feed) gridLayout() fyne.Layout {
+	if f.compact {
+		return layout.NewVBoxLayout()
+	}
+	return f.cardGrid()
+}
+
+// thumbnailSize returns the size of the thumbnails in the current view and mode.
+func (f *feed) thumbnailSize() fyne.Size {
+	switch {
+	case f.view == shortsView && f.compact:
+		return compactShortThumbnailSize
+	case f.view == shortsView:
+		return shortsThumbnailSize
+	case f.compact:
+		return compactThumbnailSize
+	default:
+		return gridThumbnailSize
+	}
+}
+
+// columns returns the number of cards shown per row.
+func (f *feed) columns() int {
+	if f.compact {
+		return 1
+	}
+	return f.cardGrid().Columns(f.grid.Size().Width)
+}
+
+// setLastSeen stores the watermark, the cards keep showing which videos were
+// new when the window was opened.
+func (f *feed) setLastSeen(t time.Time) {
+	err := video.SetLastSeen(t)
+	if err != nil {
+		log.Printf("failed storing last seen: %s", err)
+		return
+	}
+	f.seen()
+}
+
+func (f *feed) markAllSeen() {
+	f.lastSeen = time.Now()
+	f.setLastSeen(f.lastSeen)
+	f.load()
+}
+
+func (f *feed) removeCard(c *videoCard) {
+	i := slices.Index(f.cards, c)
+	if i < 0 {
+		return
+	}
+	f.cards = slices.Delete(f.cards, i, i+1)
+	f.grid.Remove(c.object)
+	f.grid.Refresh()
+
+	if f.anchor >= i {
+		f.anchor = -1
+	}
+	switch {
+	case f.focused > i:
+		f.focused--
+	case f.focused == i:
+		f.focused = -1
+		f.moveFocus(min(i, len(f.cards)-1))
+	}
+}
+
+func (f *feed) focusCard(c *videoCard) {
+	f.moveFocus(slices.Index(f.cards, c))
+}
+
+// moveFocus focuses the card at index i, clamped to the available cards.
+func (f *feed) moveFocus(i int) {
+	if len(f.cards) == 0 {
+		f.focused = -1
+		return
+	}
+	i = max(0, min(i, len(f.cards)-1))
+
+	if f.focused >= 0 && f.focused < len(f.cards) {
+		f.cards[f.focused].setFocused(false)
+	}
+	f.focused = i
+	f.cards[i].setFocused(true)
+	f.scrollTo(f.cards[i])
+}
+
+// scrollTo scrolls just far enough to make the card fully visible.
+func (f *feed) scrollTo(c *videoCard) {
+	offset := f.scroll.Offset
+	top := c.object.Position().Y
+	bottom := top + c.object.Size().Height
+	visible := f.scroll.Size().Height
+
+	if top < offset.Y {
+		offset.Y = top
+	} else if bottom > offset.Y+visible {
+		offset.Y = bottom - visible
+	}
+	f.scroll.ScrollToOffset(offset)
+}
+
+func (f *feed) focusedCard() *videoCard {
+	if f.focused < 0 || f.focused >= len(f.cards) {
+		return nil
+	}
+	return f.cards[f.focused]
+}
+
+// registerKeys binds the keyboard navigation. Fyne only treats key
+// combinations with a modifier as shortcuts, plain keys are handled by the
+// canvas whenever no widget, like the search entry, has the focus.
+func (f *feed) registerKeys() {
+	canvas := f.window.Canvas()
+
+	canvas.AddShortcut(&desktop.CustomShortcut{
+		KeyName:  fyne.KeyF,
+		Modifier: fyne.KeyModifierShortcutDefault,
+	}, func(fyne.Shortcut) {
+		canvas.Focus(f.search)
+	})
+	canvas.AddShortcut(&desktop.CustomShortcut{
+		KeyName:  fyne.KeyR,
+		Modifier: fyne.KeyModifierShortcutDefault,
+	}, func(fyne.Shortcut) {
+		f.refresh()
+	})
+
+	canvas.SetOnTypedKey(func(event *fyne.KeyEvent) {
+		switch event.Name {
+		case fyne.KeyLeft:
+			f.moveFocus(f.focused - 1)
+		case fyne.KeyRight:
+			f.moveFocus(f.focused + 1)
+		case fyne.KeyUp:
+			f.moveFocus(f.focused - f.columns())
+		case fyne.KeyDown:
+			f.moveFocus(f.focused + f.columns())
+		case fyne.KeyReturn, fyne.KeyEnter:
+			if card := f.focusedCard(); card != nil {
+				card.watch()
+			}
+		case fyne.KeySpace:
+			if card := f.focusedCard(); card != nil {
+				if !f.selecting {
+					f.setSelecting(true)
+				}
+				f.toggleSelection(card, false)
+			}
+		case fyne.KeyEscape:
+			if f.selecting {
+				f.setSelecting(false)
+			}
+		}
+	})
+
+	canvas.SetOnTypedRune(func(r rune) {
+		switch r {
+		case 'h':
+			f.moveFocus(f.focused - 1)
+		case 'l':
+			f.moveFocus(f.focused + 1)
+		case 'k':
+			f.moveFocus(f.focused - f.columns())
+		case 'j':
+			f.moveFocus(f.focused + f.columns())
+		case '/':
+			canvas.Focus(f.search)
+		case 'r':
+			f.refresh()
+		case '?':
+			f.showHelp()
+		case 'c':
+			if card := f.focusedCard(); card != nil {
+				if !f.selecting {
+					f.setSelecting(true)
+				}
+				card.setSelected(true)
+				f.selectChannels()
+			}
+		}
+
+		if len(f.selectedCards()) > 0 {
+			switch r {
+			case 'x':
+				f.hideSelected()
+			case 'q':
+				f.queueSelected()
+			case 'w':
+				f.markSelectedWatched()
+			}
+			return
+		}
+
+		card := f.focusedCard()
+		if card == nil {
+			return
+		}
+		switch r {
+		case 'i':
+			card.showDetail()
+		case 'x':
+			card.hide()
+		case 'q':
+			card.toggleQueue()
+		case 'w':
+			card.toggleWatched()
+		}
+	})
+}
+
+func (f *feed) showHelp() {
+	rows := container.NewGridWithColumns(2)
+	for _, binding := range keyBindings {
+		rows.Add(widget.NewLabelWithStyle(binding.keys, fyne.TextAlignLeading, fyne.TextStyle{Monospace: true}))
+		rows.Add(widget.NewLabel(binding.description))
+	}
+	dialog.ShowCustom("Keyboard shortcuts", "Close", rows, f.window)
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+)
+
+// Cards are never narrower than minCardWidth, wide windows get more columns
+// instead of cards wider than maxCardWidth. The cards of Shorts are narrower.
+const (
+	minCardWidth      = 240
+	maxCardWidth      = 400
+	minShortCardWidth = 160
+	maxShortCardWidth = 240
+)
+
+// cardGrid arranges cards in as many columns as fit the available width.
+// Cards wider than minWidth grow in height as well, so the thumbnails
+// keep their aspect ratio. Every row is as high as its highest card.
+type cardGrid struct {
+	// width returns the available width. The scroll container asks for the
+	// MinSize before laying out the grid, so the grid's own size can be outdated.
+	width     func() float32
+	minWidth  float32
+	maxWidth  float32
+	thumbnail fyne.Size
+}
+
+func newCardGrid(width func() float32) *cardGrid {
+	return &cardGrid{width: width, minWidth: minCardWidth, maxWidth: maxCardWidth, thumbnail: gridThumbnailSize}
+}
+
+// newShortsGrid arranges the narrower cards of Shorts with vertical thumbnails.
+func newShortsGrid(width func() float32) *cardGrid {
+	return &cardGrid{width: width, minWidth: minShortCardWidth, maxWidth: maxShortCardWidth, thumbnail: shortsThumbnailSize}
+}
+
+// Columns returns the number of columns shown at the given width.
+func (g *cardGrid) Columns(width float32) int {
+	padding := theme.Padding()
+	return max(1, int((width+padding)/(g.minWidth+padding)))
+}
+
+func (g *cardGrid) cellWidth(width float32, columns int) float32 {
+	padding := theme.Padding()
+	return min((width-padding*float32(columns-1))/float32(columns), g.maxWidth)
+}
+
+// rowHeights returns the height of every row of objects at the given width.
+func (g *cardGrid) rowHeights(objects []fyne.CanvasObject, columns int, cellWidth float32) []float32 {
+	grow := (cellWidth - g.minWidth) * g.thumbnail.Height / g.thumbnail.Width
+
+	heights := make([]float32, (len(objects)+columns-1)/columns)
+	for i, object := range objects {
+		row := i / columns
+		heights[row] = max(heights[row], object.MinSize().Height+max(grow, 0))
+	}
+	return heights
+}
+
+func (g *cardGrid) Layout(objects []fyne.CanvasObject, size fyne.Size) {
+	padding := theme.Padding()
+	columns := g.Columns(size.Width)
+	cellWidth := g.cellWidth(size.Width, columns)
+
+	y := float32(0)
+	for row, height := range g.rowHeights(objects, columns, cellWidth) {
+		for column := range columns {
+			i := row*columns + column
+			if i >= len(objects) {
+				break
+			}
+			objects[i].Move(fyne.NewPos(float32(column)*(cellWidth+padding), y))
+			objects[i].Resize(fyne.NewSize(cellWidth, height))
+		}
+		y += height + padding
+	}
+}
+
+func (g *cardGrid) MinSize(objects []fyne.CanvasObject) fyne.Size {
+	width := max(g.width(), g.minWidth)
+	columns := g.Columns(width)
+
+	height := float32(0)
+	heights := g.rowHeights(objects, columns, g.cellWidth(width, columns))
+	for _, rowHeight := range heights {
+		height += rowHeight
+	}
+	if len(heights) > 1 {
+		height += theme.Padding() * float32(len(heights)-1)
+	}
+	return fyne.NewSize(g.minWidth, height)
+}
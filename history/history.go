@@ -0,0 +1,184 @@
+// Package history exports the stored videos together with what happened to
+// them, i.e. whether they were hidden, watched or queued and when.
+package history
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aaronzipp/deeptube/database"
+)
+
+// Formats the history can be written in.
+const (
+	FormatCSV   = "csv"
+	FormatJSON  = "json"
+	FormatJSONL = "jsonl"
+)
+
+var Formats = []string{FormatCSV, FormatJSON, FormatJSONL}
+
+// Entry is a single video. Times are nil when they never happened.
+type Entry struct {
+	VideoID     string     `json:"video_id"`
+	Title       string     `json:"title"`
+	Channel     string     `json:"channel"`
+	Categories  []string   `json:"categories"`
+	PublishedAt time.Time  `json:"published_at"`
+	FetchedAt   *time.Time `json:"fetched_at"`
+	Duration    int        `json:"duration_seconds"`
+	Position    int        `json:"position_seconds"`
+	Hidden      bool       `json:"hidden"`
+	Watched     bool       `json:"watched"`
+	WatchedAt   *time.Time `json:"watched_at"`
+	Queued      bool       `json:"queued"`
+	QueuedAt    *time.Time `json:"queued_at"`
+}
+
+// Filter limits the entries to videos published in [From, Until) and in
+// Category. Zero values don't limit anything.
+type Filter struct {
+	From     time.Time
+	Until    time.Time
+	Category string
+}
+
+// Load returns the entries passing filter, oldest first.
+func Load(filter Filter) ([]Entry, error) {
+	ctx := context.Background()
+	db, err := database.Open()
+	if err != nil {
+		return nil, err
+	}
+
+	queries := database.New(db)
+
+	until := "9999-12-31 23:59:59"
+	if !filter.Until.IsZero() {
+		until = formatTime(filter.Until)
+	}
+	videos, err := queries.FetchHistory(ctx, database.FetchHistoryParams{
+		PublishedFrom:  sql.NullString{String: formatTime(filter.From), Valid: true},
+		PublishedUntil: sql.NullString{String: until, Valid: true},
+		Category:       filter.Category,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, len(videos))
+	for i, vid := range videos {
+		publishedAt, _ := time.Parse("2006-01-02 15:04:05", vid.PublishedAt.String)
+		var categories []string
+		if vid.Categories.String != "" {
+			categories = strings.Split(vid.Categories.String, ",")
+		}
+		entries[i] = Entry{
+			VideoID:     vid.VideoID,
+			Title:       vid.Title.String,
+			Channel:     vid.ChannelName.String,
+			Categories:  categories,
+			PublishedAt: publishedAt,
+			FetchedAt:   parseTime(vid.FetchedAt),
+			Duration:    int(vid.Hours.Int64*3600 + vid.Minutes.Int64*60 + vid.Seconds.Int64),
+			Position:    int(vid.Position.Int64),
+			Hidden:      vid.IsHidden.Int64 == 1,
+			Watched:     vid.WatchedAt.Valid,
+			WatchedAt:   parseTime(vid.WatchedAt),
+			Queued:      vid.QueuedAt.Valid,
+			QueuedAt:    parseTime(vid.QueuedAt),
+		}
+	}
+	return entries, nil
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04:05")
+}
+
+func parseTime(s sql.NullString) *time.Time {
+	if !s.Valid {
+		return nil
+	}
+	t, err := time.Parse("2006-01-02 15:04:05", s.String)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// Write writes the entries as CSV with a header, as a JSON array or as JSON
+// Lines with one entry per line.
+func Write(w io.Writer, format string, entries []Entry) error {
+	switch format {
+	case FormatCSV:
+		return writeCSV(w, entries)
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if entries == nil {
+			entries = []Entry{}
+		}
+		return encoder.Encode(entries)
+	case FormatJSONL:
+		encoder := json.NewEncoder(w)
+		for _, entry := range entries {
+			err := encoder.Encode(entry)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown history format %q, expected one of %s", format, strings.Join(Formats, ", "))
+	}
+}
+
+var csvHeader = []string{
+	"video_id", "title", "channel", "categories", "published_at", "fetched_at",
+	"duration_seconds", "position_seconds", "hidden", "watched", "watched_at", "queued", "queued_at",
+}
+
+func writeCSV(w io.Writer, entries []Entry) error {
+	writer := csv.NewWriter(w)
+	err := writer.Write(csvHeader)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		err = writer.Write([]string{
+			entry.VideoID,
+			entry.Title,
+			entry.Channel,
+			strings.Join(entry.Categories, ","),
+			entry.PublishedAt.Format(time.RFC3339),
+			csvTime(entry.FetchedAt),
+			strconv.Itoa(entry.Duration),
+			strconv.Itoa(entry.Position),
+			strconv.FormatBool(entry.Hidden),
+			strconv.FormatBool(entry.Watched),
+			csvTime(entry.WatchedAt),
+			strconv.FormatBool(entry.Queued),
+			csvTime(entry.QueuedAt),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func csvTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
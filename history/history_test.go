@@ -0,0 +1,54 @@
+package history
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestWrite(t *testing.T) {
+	watchedAt := time.Date(2025, 3, 2, 20, 0, 0, 0, time.UTC)
+	entries := []Entry{
+		{
+			VideoID:     "abc",
+			Title:       "Parsers, explained",
+			Channel:     "Compilers",
+			Categories:  []string{"Tech", "Programming"},
+			PublishedAt: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+			Duration:    754,
+			Watched:     true,
+			WatchedAt:   &watchedAt,
+		},
+	}
+
+	testData := []struct {
+		format string
+		output string
+	}{
+		{
+			format: FormatCSV,
+			output: "video_id,title,channel,categories,published_at,fetched_at,duration_seconds,position_seconds,hidden,watched,watched_at,queued,queued_at\n" +
+				`abc,"Parsers, explained",Compilers,"Tech,Programming",2025-03-01T12:00:00Z,,754,0,false,true,2025-03-02T20:00:00Z,false,` + "\n",
+		},
+		{
+			format: FormatJSONL,
+			output: `{"video_id":"abc","title":"Parsers, explained","channel":"Compilers","categories":["Tech","Programming"],` +
+				`"published_at":"2025-03-01T12:00:00Z","fetched_at":null,"duration_seconds":754,"position_seconds":0,` +
+				`"hidden":false,"watched":true,"watched_at":"2025-03-02T20:00:00Z","queued":false,"queued_at":null}` + "\n",
+		},
+	}
+
+	for _, tt := range testData {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := Write(&buf, tt.format, entries)
+			if err != nil {
+				t.Fatalf("Got an unexpected error: %q", err)
+			}
+
+			if buf.String() != tt.output {
+				t.Errorf("Got\n%s\nwant\n%s", buf.String(), tt.output)
+			}
+		})
+	}
+}
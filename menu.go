@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/aaronzipp/deeptube/backup"
+	"github.com/aaronzipp/deeptube/config"
+	"github.com/aaronzipp/deeptube/youtube"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+func (f *feed) newMainMenu() *fyne.MainMenu {
+	hideShortsItem := fyne.NewMenuItem("Hide Shorts", nil)
+	hideShortsItem.Checked = f.hideShorts
+
+	menu := fyne.NewMainMenu(
+		fyne.NewMenu("File",
+			fyne.NewMenuItem("Import subscriptions…", f.showImport),
+			fyne.NewMenuItem("Export subscriptions…", f.showExport),
+			fyne.NewMenuItemSeparator(),
+			fyne.NewMenuItem("Import history…", f.showHistoryImport),
+		),
+		fyne.NewMenu("View", hideShortsItem),
+	)
+	hideShortsItem.Action = func() {
+		hideShortsItem.Checked = !hideShortsItem.Checked
+		f.setHideShorts(hideShortsItem.Checked)
+		menu.Refresh()
+	}
+	return menu
+}
+
+// showImport asks for a Takeout CSV or an OPML file and adds its channels
+// to the subscriptions, followed by a refresh to fetch their videos.
+func (f *feed) showImport() {
+	open := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, f.window)
+			return
+		}
+		if reader == nil {
+			return
+		}
+		reader.Close()
+
+		cfg, err := config.Load(config.DefaultPath)
+		if err != nil {
+			dialog.ShowError(err, f.window)
+			return
+		}
+		added, err := youtube.ImportSubscriptions(reader.URI().Path(), cfg)
+		if err != nil {
+			dialog.ShowError(err, f.window)
+			return
+		}
+		dialog.ShowInformation("Import subscriptions",
+			fmt.Sprintf("Added %d subscriptions to %s.", len(added), cfg.SubscriptionsPath), f.window)
+		if len(added) > 0 {
+			f.refresh()
+		}
+	}, f.window)
+	open.SetFilter(storage.NewExtensionFileFilter([]string{".csv", ".opml", ".xml"}))
+	open.Show()
+}
+
+// exportExtensions are the file extensions suggested for the export formats.
+var exportExtensions = map[string]string{
+	youtube.FormatOPML:     ".opml",
+	youtube.FormatNewPipe:  ".json",
+	youtube.FormatFreeTube: ".db",
+	youtube.FormatJSON:     ".json",
+}
+
+// showExport asks for the format and the file to export the subscriptions to.
+func (f *feed) showExport() {
+	format := widget.NewSelect(youtube.ExportFormats, nil)
+	format.SetSelected(youtube.FormatOPML)
+
+	items := []*widget.FormItem{widget.NewFormItem("Format", format)}
+	dialog.ShowForm("Export subscriptions", "Next", "Cancel", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, f.window)
+				return
+			}
+			if writer == nil {
+				return
+			}
+			cfg, err := config.Load(config.DefaultPath)
+			if err == nil {
+				err = exportSubscriptions(writer, cfg, format.Selected)
+			}
+			closeErr := writer.Close()
+			if err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				dialog.ShowError(err, f.window)
+			}
+		}, f.window)
+		save.SetFileName("subscriptions" + exportExtensions[format.Selected])
+		save.Show()
+	}, f.window)
+}
+
+// showHistoryImport asks for a NewPipe or FreeTube backup and takes over
+// which videos were watched and queued.
+func (f *feed) showHistoryImport() {
+	open := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, f.window)
+			return
+		}
+		if reader == nil {
+			return
+		}
+		reader.Close()
+
+		result, err := backup.ImportFile(reader.URI().Path())
+		if err != nil {
+			dialog.ShowError(err, f.window)
+			return
+		}
+		dialog.ShowInformation("Import history", fmt.Sprintf(
+			"Marked %d videos as watched and queued %d, %d of them were new.",
+			result.Watched, result.Queued, result.Added,
+		), f.window)
+		f.reload()
+	}, f.window)
+	open.SetFilter(storage.NewExtensionFileFilter([]string{".zip", ".db", ".json", ".ndjson"}))
+	open.Show()
+}
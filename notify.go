@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aaronzipp/deeptube/video"
+
+	"fyne.io/fyne/v2"
+)
+
+// maxNotifications is the number of videos notified about one by one, when
+// more arrive at once a single notification lists their channels.
+const maxNotifications = 3
+
+// maxUploadItems is the number of notified videos kept in the tray menu.
+const maxUploadItems = 10
+
+// notifyUploads sends desktop notifications for the new videos of channels
+// with notifications enabled and returns the notified videos. Only videos
+// published after the last refresh started are genuinely new, the others
+// were added by fetching a feed for the first time or more of it, so nothing
+// is notified before the first successful refresh. Hidden Shorts are left out.
+func notifyUploads(a fyne.App, added video.Videos, lastRefresh time.Time) video.Videos {
+	hideShorts := a.Preferences().Bool(prefHideShorts)
+	var videos video.Videos
+	for _, vid := range added {
+		if lastRefresh.IsZero() || !vid.PublishedAt.After(lastRefresh) {
+			continue
+		}
+		if vid.Notify && !(hideShorts && vid.Type == video.ShortVideo) {
+			videos = append(videos, vid)
+		}
+	}
+
+	if len(videos) <= maxNotifications {
+		for _, vid := range videos {
+			a.SendNotification(fyne.NewNotification(vid.ChannelName, vid.Title))
+		}
+		return videos
+	}
+
+	var channels []string
+	for _, vid := range videos {
+		if !slices.Contains(channels, vid.ChannelName) {
+			channels = append(channels, vid.ChannelName)
+		}
+	}
+	a.SendNotification(fyne.NewNotification(
+		fmt.Sprintf("%d new videos", len(videos)),
+		strings.Join(channels, ", "),
+	))
+	return videos
+}
+
+// uploadItems creates the tray menu items opening the notified videos, as
+// notifications themselves can't be clicked.
+func uploadItems(videos video.Videos) []*fyne.MenuItem {
+	items := make([]*fyne.MenuItem, len(videos))
+	for i, vid := range videos {
+		label := fmt.Sprintf("%s: %s", vid.ChannelName, vid.Title)
+		items[i] = fyne.NewMenuItem(label, func() {
+			err := videoPlayer.Open(vid, vid.Position)
+			if err != nil {
+				log.Printf("failed opening %s: %s", vid.VideoId, err)
+			}
+		})
+	}
+	return items
+}
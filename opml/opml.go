@@ -0,0 +1,94 @@
+// Package opml reads and writes OPML outlines, the format feed readers and
+// YouTube clients like NewPipe and FreeTube exchange subscriptions in.
+package opml
+
+import (
+	"encoding/xml"
+	"io"
+	"slices"
+	"strings"
+)
+
+type Document struct {
+	XMLName xml.Name `xml:"opml"`
+	Version string   `xml:"version,attr"`
+	Head    Head     `xml:"head"`
+	Body    Body     `xml:"body"`
+}
+
+type Head struct {
+	Title string `xml:"title,omitempty"`
+}
+
+type Body struct {
+	Outlines []Outline `xml:"outline"`
+}
+
+// Outline is either a feed, with an XMLURL, or a group of nested outlines.
+type Outline struct {
+	Text    string `xml:"text,attr"`
+	Title   string `xml:"title,attr,omitempty"`
+	Type    string `xml:"type,attr,omitempty"`
+	XMLURL  string `xml:"xmlUrl,attr,omitempty"`
+	HTMLURL string `xml:"htmlUrl,attr,omitempty"`
+	// Category holds comma separated tags.
+	Category string    `xml:"category,attr,omitempty"`
+	Outlines []Outline `xml:"outline"`
+}
+
+// Feed is a feed outline together with the groups it is nested in and its tags.
+type Feed struct {
+	Outline
+	Groups []string
+}
+
+func Parse(r io.Reader) (Document, error) {
+	var doc Document
+	err := xml.NewDecoder(r).Decode(&doc)
+	return doc, err
+}
+
+// Write writes the document with an XML header, indented.
+func (d Document) Write(w io.Writer) error {
+	if d.Version == "" {
+		d.Version = "2.0"
+	}
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	err = encoder.Encode(d)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+// Feeds returns every outline with a feed URL, however deeply nested.
+func (d Document) Feeds() []Feed {
+	return feeds(d.Body.Outlines, nil)
+}
+
+func feeds(outlines []Outline, groups []string) []Feed {
+	var result []Feed
+	for _, outline := range outlines {
+		if outline.XMLURL == "" {
+			result = append(result, feeds(outline.Outlines, append(groups[:len(groups):len(groups)], outline.Text))...)
+			continue
+		}
+
+		feedGroups := append([]string(nil), groups...)
+		for _, tag := range strings.Split(outline.Category, ",") {
+			// Categories may be paths like "/Tech/News", the last part is the tag.
+			tag = strings.TrimSpace(tag[strings.LastIndex(tag, "/")+1:])
+			if tag != "" && !slices.Contains(feedGroups, tag) {
+				feedGroups = append(feedGroups, tag)
+			}
+		}
+		result = append(result, Feed{Outline: outline, Groups: feedGroups})
+	}
+	return result
+}
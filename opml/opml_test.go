@@ -0,0 +1,58 @@
+package opml
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const newPipeExport = `<?xml version="1.0" encoding="UTF-8"?>
+<opml version="1.1">
+  <body>
+    <outline text="Tech">
+      <outline text="Compilers" xmlUrl="https://www.youtube.com/feeds/videos.xml?channel_id=UC0000000000000000000001"/>
+    </outline>
+    <outline text="Cooking" xmlUrl="https://www.youtube.com/feeds/videos.xml?channel_id=UC0000000000000000000002" category="/Food/Recipes,Kitchen"/>
+  </body>
+</opml>`
+
+func TestFeeds(t *testing.T) {
+	doc, err := Parse(strings.NewReader(newPipeExport))
+	if err != nil {
+		t.Fatalf("Got an unexpected error: %q", err)
+	}
+
+	var got []string
+	for _, feed := range doc.Feeds() {
+		got = append(got, feed.Text+": "+strings.Join(feed.Groups, ","))
+	}
+	want := []string{"Compilers: Tech", "Cooking: Recipes,Kitchen"}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %q, want %q", got, want)
+	}
+}
+
+func TestWriteParse(t *testing.T) {
+	doc := Document{
+		Head: Head{Title: "Subscriptions"},
+		Body: Body{Outlines: []Outline{
+			{Text: "Music", Outlines: []Outline{{Text: "Band", Type: "rss", XMLURL: "https://example.com/feed"}}},
+		}},
+	}
+
+	var buf bytes.Buffer
+	err := doc.Write(&buf)
+	if err != nil {
+		t.Fatalf("Got an unexpected error: %q", err)
+	}
+	parsed, err := Parse(&buf)
+	if err != nil {
+		t.Fatalf("Got an unexpected error: %q", err)
+	}
+
+	if !reflect.DeepEqual(parsed.Body, doc.Body) || parsed.Version != "2.0" {
+		t.Errorf("Got %+v, want %+v", parsed, doc)
+	}
+}
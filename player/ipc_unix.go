@@ -0,0 +1,17 @@
+//go:build !windows
+
+package player
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+)
+
+func ipcPath(videoID string) string {
+	return filepath.Join(os.TempDir(), "deeptube-mpv-"+videoID+".sock")
+}
+
+func dialIPC(path string) (net.Conn, error) {
+	return net.Dial("unix", path)
+}
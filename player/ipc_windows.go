@@ -0,0 +1,18 @@
+//go:build windows
+
+package player
+
+import (
+	"errors"
+	"net"
+)
+
+// mpv listens on a named pipe on Windows, which the standard library can't
+// dial, so positions are not tracked there.
+func ipcPath(videoID string) string {
+	return `\\.\pipe\deeptube-mpv-` + videoID
+}
+
+func dialIPC(path string) (net.Conn, error) {
+	return nil, errors.New("mpv IPC is not supported on windows")
+}
@@ -0,0 +1,96 @@
+package player
+
+import (
+	"bufio"
+	"encoding/json"
+	"math"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aaronzipp/deeptube/video"
+)
+
+const pollInterval = 5 * time.Second
+
+// mpv needs a while to resolve the stream with yt-dlp before the IPC socket
+// accepts connections.
+const connectTimeout = 30 * time.Second
+
+type mpvResponse struct {
+	Data      any    `json:"data"`
+	Error     string `json:"error"`
+	RequestID int    `json:"request_id"`
+}
+
+func isMpv(command string) bool {
+	name := strings.TrimSuffix(filepath.Base(command), ".exe")
+	return name == "mpv"
+}
+
+// track polls the playback position over mpv's JSON IPC until mpv exits and
+// stores every change.
+func (p *Player) track(vid video.Video, socket string, done <-chan struct{}) {
+	deadline := time.Now().Add(connectTimeout)
+	conn, err := dialIPC(socket)
+	for err != nil {
+		if time.Now().After(deadline) {
+			return
+		}
+		select {
+		case <-done:
+			return
+		case <-time.After(time.Second):
+		}
+		conn, err = dialIPC(socket)
+	}
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	request := []byte(`{"command": ["get_property", "time-pos"], "request_id": 1}` + "\n")
+	last := vid.Position
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+
+		_, err := conn.Write(request)
+		if err != nil {
+			return
+		}
+		position, ok, err := readPosition(reader)
+		if err != nil {
+			return
+		}
+		if ok && position != last {
+			last = position
+			p.progress(vid, position)
+		}
+	}
+}
+
+// readPosition skips the events mpv sends until the reply to our request
+// arrives. ok is false while mpv doesn't know the position yet.
+func readPosition(reader *bufio.Reader) (position int, ok bool, err error) {
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			return 0, false, err
+		}
+
+		var response mpvResponse
+		if json.Unmarshal(line, &response) != nil || response.RequestID != 1 {
+			continue
+		}
+		seconds, isNumber := response.Data.(float64)
+		if response.Error != "success" || !isNumber {
+			return 0, false, nil
+		}
+		return int(math.Round(seconds)), true, nil
+	}
+}
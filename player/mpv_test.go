@@ -0,0 +1,52 @@
+package player
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+)
+
+func TestReadPosition(t *testing.T) {
+	testData := []struct {
+		input    string
+		position int
+		ok       bool
+	}{
+		{
+			input:    `{"event":"playback-restart"}` + "\n" + `{"data":62.6,"error":"success","request_id":1}` + "\n",
+			position: 63,
+			ok:       true,
+		},
+		{
+			input:    `{"error":"property unavailable","request_id":1}` + "\n",
+			position: 0,
+			ok:       false,
+		},
+	}
+
+	for _, tt := range testData {
+		position, ok, err := readPosition(bufio.NewReader(strings.NewReader(tt.input)))
+
+		if err != nil {
+			t.Fatalf("Got an unexpected error: %q", err)
+		}
+		if position != tt.position || ok != tt.ok {
+			t.Errorf("Got %d, %t, want %d, %t", position, ok, tt.position, tt.ok)
+		}
+	}
+}
+
+func TestIsMpv(t *testing.T) {
+	for command, want := range map[string]bool{
+		"mpv":                true,
+		"/usr/bin/mpv":       true,
+		"vlc":                false,
+		"/usr/bin/mpv-extra": false,
+	} {
+		got := isMpv(command)
+
+		if got != want {
+			t.Errorf("Got %t for %q, want %t", got, command, want)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package player
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/aaronzipp/deeptube/config"
+	"github.com/aaronzipp/deeptube/video"
+)
+
+type Player struct {
+	mu         sync.Mutex
+	settings   config.Player
+	onProgress []func(videoID string, seconds int)
+}
+
+func New(settings config.Player) *Player {
+	return &Player{settings: settings}
+}
+
+// SetSettings replaces the launchers, videos playing already keep theirs.
+func (p *Player) SetSettings(settings config.Player) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.settings = settings
+}
+
+// Open plays the video starting at the given second, using the launcher of
+// the video's first category that has one.
+func (p *Player) Open(vid video.Video, start int) error {
+	launcher := p.launcher(vid)
+
+	if launcher.Command != "" {
+		args := strings.Fields(launcher.Command)
+		if len(args) == 0 {
+			return errors.New("the player command is empty")
+		}
+		for i, arg := range args {
+			args[i] = expand(arg, vid, start)
+		}
+		if !isMpv(args[0]) {
+			_, err := run(exec.Command(args[0], args[1:]...))
+			return err
+		}
+
+		socket := ipcPath(vid.VideoId)
+		args = append(args, "--input-ipc-server="+socket)
+		done, err := run(exec.Command(args[0], args[1:]...))
+		if err != nil {
+			return err
+		}
+		go p.track(vid, socket, done)
+		return nil
+	}
+
+	return openBrowser(expand(launcher.URL, vid, start))
+}
+
+// OnProgress registers a callback that is called from a background goroutine
+// whenever a player reports a new position for a video.
+func (p *Player) OnProgress(callback func(videoID string, seconds int)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onProgress = append(p.onProgress, callback)
+}
+
+func (p *Player) progress(vid video.Video, seconds int) {
+	err := vid.SetPosition(seconds)
+	if err != nil {
+		log.Printf("failed storing position of %s: %s", vid.VideoId, err)
+		return
+	}
+
+	p.mu.Lock()
+	callbacks := p.onProgress
+	p.mu.Unlock()
+	for _, callback := range callbacks {
+		callback(vid.VideoId, seconds)
+	}
+}
+
+func (p *Player) launcher(vid video.Video) config.Launcher {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, category := range vid.Categories {
+		launcher, ok := p.settings.Categories[category]
+		if ok {
+			return launcher
+		}
+	}
+	// The default URL is preset, so a configured command takes precedence over it.
+	return p.settings.Default
+}
+
+func expand(template string, vid video.Video, start int) string {
+	return strings.NewReplacer(
+		"{url}", vid.YouTubeLinkAt(start),
+		"{id}", vid.VideoId,
+		"{start}", strconv.Itoa(start),
+	).Replace(template)
+}
+
+func openBrowser(url string) error {
+	var err error
+	switch runtime.GOOS {
+	case "windows":
+		_, err = run(exec.Command("rundll32", "url.dll,FileProtocolHandler", url))
+	case "darwin":
+		_, err = run(exec.Command("open", url))
+	default:
+		_, err = run(exec.Command("xdg-open", url))
+	}
+	return err
+}
+
+// run starts cmd and returns once it is running, together with a channel that
+// is closed when it exits. Failures after the start are only logged, with
+// what the command wrote to stderr.
+func run(cmd *exec.Cmd) (<-chan struct{}, error) {
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	err := cmd.Start()
+	if err != nil {
+		return nil, fmt.Errorf("failed starting %s: %w", cmd.Path, err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		err := cmd.Wait()
+		if err != nil {
+			log.Printf("%s failed: %s\n%s", strings.Join(cmd.Args, " "), err, stderr.Bytes())
+		}
+	}()
+	return done, nil
+}
@@ -0,0 +1,45 @@
+package player
+
+import (
+	"testing"
+
+	"github.com/aaronzipp/deeptube/config"
+	"github.com/aaronzipp/deeptube/video"
+)
+
+func TestExpand(t *testing.T) {
+	vid := video.Video{VideoId: "abc"}
+
+	got := expand("mpv --start={start} {url}", vid, 90)
+	want := "mpv --start=90 https://www.youtube.com/watch_popup?v=abc&t=90s"
+
+	if got != want {
+		t.Errorf("Got %q, want %q", got, want)
+	}
+}
+
+func TestLauncher(t *testing.T) {
+	p := New(config.Player{
+		Default: config.Launcher{URL: "{url}"},
+		Categories: map[string]config.Launcher{
+			"Music": {Command: "mpv --no-video {url}"},
+		},
+	})
+
+	testData := []struct {
+		categories []string
+		output     config.Launcher
+	}{
+		{categories: nil, output: config.Launcher{URL: "{url}"}},
+		{categories: []string{"Tech"}, output: config.Launcher{URL: "{url}"}},
+		{categories: []string{"Tech", "Music"}, output: config.Launcher{Command: "mpv --no-video {url}"}},
+	}
+
+	for _, tt := range testData {
+		got := p.launcher(video.Video{Categories: tt.categories})
+
+		if got != tt.output {
+			t.Errorf("Got %+v for %v, want %+v", got, tt.categories, tt.output)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package refresh
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/aaronzipp/deeptube/config"
+)
+
+// Scheduler runs a refresh function on a fixed interval and on demand.
+// All runs happen on a single goroutine, so they never overlap, and triggers
+// arriving while a run is in progress collapse into one follow-up run.
+type Scheduler struct {
+	settings config.Refresh
+	run      func() error
+	trigger  chan struct{}
+	jobs     chan func() error
+	failures int
+
+	// updates holds the latest settings the loop didn't pick up yet.
+	updatesMu sync.Mutex
+	updates   chan config.Refresh
+}
+
+func New(settings config.Refresh, run func() error) *Scheduler {
+	return &Scheduler{
+		settings: settings,
+		run:      run,
+		trigger:  make(chan struct{}, 1),
+		jobs:     make(chan func() error),
+		updates:  make(chan config.Refresh, 1),
+	}
+}
+
+// Start refreshes once right away and then keeps refreshing in the background.
+func (s *Scheduler) Start() {
+	go s.loop()
+}
+
+// Trigger requests a refresh as soon as possible, regardless of quiet hours.
+func (s *Scheduler) Trigger() {
+	select {
+	case s.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// Do runs job on the refresh goroutine, after the refresh in progress if
+// there is one. Unlike a triggered refresh it leaves the schedule alone.
+func (s *Scheduler) Do(job func() error) {
+	go func() {
+		s.jobs <- job
+	}()
+}
+
+// SetSettings replaces the interval, backoff and quiet hours. The refresh
+// already scheduled keeps its time unless the new interval is shorter than
+// the time left.
+func (s *Scheduler) SetSettings(settings config.Refresh) {
+	s.updatesMu.Lock()
+	defer s.updatesMu.Unlock()
+	// Settings the loop hasn't picked up yet are outdated.
+	select {
+	case <-s.updates:
+	default:
+	}
+	s.updates <- settings
+}
+
+func (s *Scheduler) loop() {
+	s.runOnce()
+
+	timer := time.NewTimer(0)
+	var due time.Time
+	schedule := func(delay time.Duration) {
+		due = time.Now().Add(delay)
+		timer.Reset(delay)
+	}
+	schedule(s.nextDelay())
+	for {
+		select {
+		case <-timer.C:
+			now := time.Now()
+			if inQuietHours(now, s.settings.QuietHours) {
+				schedule(untilQuietHoursEnd(now, s.settings.QuietHours))
+				continue
+			}
+		case <-s.trigger:
+			timer.Stop()
+		case job := <-s.jobs:
+			err := job()
+			if err != nil {
+				log.Printf("refresh job failed: %s", err)
+			}
+			continue
+		case settings := <-s.updates:
+			s.settings = settings
+			schedule(min(time.Until(due), s.nextDelay()))
+			continue
+		}
+
+		s.runOnce()
+		schedule(s.nextDelay())
+	}
+}
+
+func (s *Scheduler) runOnce() {
+	err := s.run()
+	if err != nil {
+		s.failures++
+		log.Printf("refresh failed (attempt %d): %s", s.failures, err)
+		return
+	}
+	s.failures = 0
+}
+
+func (s *Scheduler) nextDelay() time.Duration {
+	return backoff(s.settings.Interval, s.settings.MaxBackoff, s.failures)
+}
+
+// backoff doubles the interval for every consecutive failure, capped at max.
+func backoff(interval, max time.Duration, failures int) time.Duration {
+	delay := interval
+	for range failures {
+		delay *= 2
+		if delay >= max {
+			return max
+		}
+	}
+	return delay
+}
+
+func inQuietHours(t time.Time, quiet config.QuietHours) bool {
+	if quiet.Start == quiet.End {
+		return false
+	}
+	hour := t.Hour()
+	if quiet.Start < quiet.End {
+		return hour >= quiet.Start && hour < quiet.End
+	}
+	// The window wraps around midnight, e.g. 23 to 7.
+	return hour >= quiet.Start || hour < quiet.End
+}
+
+func untilQuietHoursEnd(t time.Time, quiet config.QuietHours) time.Duration {
+	end := time.Date(t.Year(), t.Month(), t.Day(), quiet.End, 0, 0, 0, t.Location())
+	if !end.After(t) {
+		end = end.AddDate(0, 0, 1)
+	}
+	return end.Sub(t)
+}
@@ -0,0 +1,81 @@
+package refresh
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aaronzipp/deeptube/config"
+)
+
+func TestBackoff(t *testing.T) {
+	testData := []struct {
+		failures int
+		output   time.Duration
+	}{
+		{failures: 0, output: 30 * time.Minute},
+		{failures: 1, output: time.Hour},
+		{failures: 2, output: 2 * time.Hour},
+		{failures: 10, output: 6 * time.Hour},
+	}
+
+	for _, tt := range testData {
+		got := backoff(30*time.Minute, 6*time.Hour, tt.failures)
+
+		if got != tt.output {
+			t.Errorf("Got %s after %d failures, want %s", got, tt.failures, tt.output)
+		}
+	}
+}
+
+func TestInQuietHours(t *testing.T) {
+	testData := []struct {
+		hour   int
+		quiet  config.QuietHours
+		output bool
+	}{
+		{hour: 12, quiet: config.QuietHours{}, output: false},
+		{hour: 2, quiet: config.QuietHours{Start: 1, End: 6}, output: true},
+		{hour: 6, quiet: config.QuietHours{Start: 1, End: 6}, output: false},
+		{hour: 23, quiet: config.QuietHours{Start: 23, End: 7}, output: true},
+		{hour: 3, quiet: config.QuietHours{Start: 23, End: 7}, output: true},
+		{hour: 12, quiet: config.QuietHours{Start: 23, End: 7}, output: false},
+	}
+
+	for _, tt := range testData {
+		input := time.Date(2025, 1, 1, tt.hour, 30, 0, 0, time.UTC)
+		got := inQuietHours(input, tt.quiet)
+
+		if got != tt.output {
+			t.Errorf("Got %t for %d:30 in %+v, want %t", got, tt.hour, tt.quiet, tt.output)
+		}
+	}
+}
+
+func TestUntilQuietHoursEnd(t *testing.T) {
+	quiet := config.QuietHours{Start: 23, End: 7}
+	input := time.Date(2025, 1, 1, 23, 30, 0, 0, time.UTC)
+
+	got := untilQuietHoursEnd(input, quiet)
+	want := 7*time.Hour + 30*time.Minute
+
+	if got != want {
+		t.Errorf("Got %s, want %s", got, want)
+	}
+}
+
+func TestSetSettings(t *testing.T) {
+	s := New(config.Refresh{Interval: time.Hour}, func() error { return nil })
+
+	s.SetSettings(config.Refresh{Interval: 2 * time.Hour})
+	s.SetSettings(config.Refresh{Interval: 3 * time.Hour})
+
+	got := <-s.updates
+	if got.Interval != 3*time.Hour {
+		t.Errorf("Got interval %s, want the latest one %s", got.Interval, 3*time.Hour)
+	}
+	select {
+	case stale := <-s.updates:
+		t.Errorf("Got the outdated settings %+v as well", stale)
+	default:
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"log"
+	"sync/atomic"
+
+	"github.com/aaronzipp/deeptube/config"
+	"github.com/aaronzipp/deeptube/refresh"
+	"github.com/aaronzipp/deeptube/youtube"
+
+	"fyne.io/fyne/v2"
+)
+
+// currentConfig is the last valid config, it is replaced whenever the config
+// files change while refreshes read it on the scheduler goroutine.
+var currentConfig atomic.Pointer[config.Config]
+
+// watchConfig reloads the config when its files change. Invalid changes are
+// passed to showProblems and otherwise ignored, valid ones replace
+// currentConfig, clear the problems, apply the settings and fetch the videos
+// of added feeds. The thumbnail and retention limits are read on every refresh.
+func watchConfig(scheduler *refresh.Scheduler, showProblems func(error)) (*config.Watcher, error) {
+	return config.Watch(currentConfig.Load().Path, func(cfg config.Config, err error) {
+		if err != nil {
+			log.Printf("ignoring the changed config: %s", err)
+			showProblems(err)
+			return
+		}
+		old := currentConfig.Swap(&cfg)
+		showProblems(nil)
+		videoPlayer.SetSettings(cfg.Player)
+		if cfg.Refresh != old.Refresh {
+			scheduler.SetSettings(cfg.Refresh)
+		}
+		fyne.Do(func() {
+			if mainFeed != nil {
+				mainFeed.setPlaylists(cfg.Playlists)
+				changed := mainFeed.ui != cfg.UI
+				mainFeed.ui = cfg.UI
+				if changed && mainFeed.visible {
+					mainFeed.reload()
+				}
+			}
+		})
+
+		// A new key may make the failing refreshes work, so all feeds are fetched.
+		if cfg.API.Key != old.API.Key {
+			scheduler.Trigger()
+			return
+		}
+		subs, playlists := config.AddedFeeds(*old, cfg)
+		if len(subs) == 0 && len(playlists) == 0 {
+			return
+		}
+		scheduler.Do(func() error {
+			_, err := youtube.RefreshFeeds(cfg.API.Key, subs, playlists)
+			if err != nil {
+				return err
+			}
+			return thumbnailQueue.EnqueueMissing(cfg.Thumbnails)
+		})
+	})
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/aaronzipp/deeptube/video"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// hideOlderChoices are the periods offered by "Hide older than".
+var hideOlderChoices = []struct {
+	label string
+	age   time.Duration
+}{
+	{"1 week", 7 * 24 * time.Hour},
+	{"2 weeks", 14 * 24 * time.Hour},
+	{"1 month", 30 * 24 * time.Hour},
+	{"3 months", 90 * 24 * time.Hour},
+	{"6 months", 182 * 24 * time.Hour},
+	{"1 year", 365 * 24 * time.Hour},
+}
+
+// newSelectionBar creates the bar with the bulk actions, shown in selection mode.
+func (f *feed) newSelectionBar() *fyne.Container {
+	f.selectionCount = widget.NewLabel("")
+
+	bar := container.NewHBox(
+		f.selectionCount,
+		layout.NewSpacer(),
+		widget.NewButton("Select channel", f.selectChannels),
+		widget.NewButtonWithIcon("Hide", theme.DeleteIcon(), f.hideSelected),
+		widget.NewButtonWithIcon("Mark watched", theme.ConfirmIcon(), f.markSelectedWatched),
+		widget.NewButtonWithIcon("Queue", theme.ContentAddIcon(), f.queueSelected),
+		widget.NewButton("Hide older than…", f.showHideOlder),
+		widget.NewButton("Done", func() { f.setSelecting(false) }),
+	)
+	bar.Hide()
+	return bar
+}
+
+func (f *feed) setSelecting(selecting bool) {
+	f.selecting = selecting
+	if selecting {
+		f.selectionBar.Show()
+	} else {
+		f.clearSelection()
+		f.selectionBar.Hide()
+	}
+	f.updateSelectionCount()
+}
+
+// toggleSelection selects or deselects c. With extend all cards between
+// the previously toggled one and c are selected instead.
+func (f *feed) toggleSelection(c *videoCard, extend bool) {
+	i := slices.Index(f.cards, c)
+	if i < 0 {
+		return
+	}
+	if extend && f.anchor >= 0 && f.anchor < len(f.cards) {
+		for _, card := range f.cards[min(f.anchor, i) : max(f.anchor, i)+1] {
+			card.setSelected(true)
+		}
+	} else {
+		c.setSelected(!c.selected)
+	}
+	f.anchor = i
+	f.updateSelectionCount()
+}
+
+// selectChannels selects every card from the channels of the selected cards.
+func (f *feed) selectChannels() {
+	channels := make(map[string]bool)
+	for _, card := range f.selectedCards() {
+		channels[card.video.ChannelName] = true
+	}
+	for _, card := range f.cards {
+		if channels[card.video.ChannelName] {
+			card.setSelected(true)
+		}
+	}
+	f.updateSelectionCount()
+}
+
+func (f *feed) clearSelection() {
+	for _, card := range f.cards {
+		card.setSelected(false)
+	}
+	f.anchor = -1
+}
+
+func (f *feed) selectedCards() []*videoCard {
+	var cards []*videoCard
+	for _, card := range f.cards {
+		if card.selected {
+			cards = append(cards, card)
+		}
+	}
+	return cards
+}
+
+func selectedVideos(cards []*videoCard) video.Videos {
+	videos := make(video.Videos, len(cards))
+	for i, card := range cards {
+		videos[i] = card.video
+	}
+	return videos
+}
+
+func (f *feed) updateSelectionCount() {
+	f.selectionCount.SetText(fmt.Sprintf("%d selected", len(f.selectedCards())))
+}
+
+func (f *feed) hideSelected() {
+	cards := f.selectedCards()
+	err := selectedVideos(cards).Hide()
+	if err != nil {
+		dialog.ShowError(err, f.window)
+		return
+	}
+	for _, card := range cards {
+		f.removeCard(card)
+	}
+	f.anchor = -1
+	f.updateSelectionCount()
+}
+
+func (f *feed) markSelectedWatched() {
+	cards := f.selectedCards()
+	err := selectedVideos(cards).MarkWatched()
+	if err != nil {
+		dialog.ShowError(err, f.window)
+		return
+	}
+	for _, card := range cards {
+		card.setWatched(true)
+	}
+}
+
+func (f *feed) queueSelected() {
+	cards := f.selectedCards()
+	err := selectedVideos(cards).Queue()
+	if err != nil {
+		dialog.ShowError(err, f.window)
+		return
+	}
+	for _, card := range cards {
+		card.video.Queued = true
+	}
+}
+
+func (f *feed) showHideOlder() {
+	labels := make([]string, len(hideOlderChoices))
+	for i, choice := range hideOlderChoices {
+		labels[i] = choice.label
+	}
+	period := widget.NewSelect(labels, nil)
+	period.SetSelectedIndex(0)
+
+	items := []*widget.FormItem{widget.NewFormItem("Hide videos older than", period)}
+	dialog.ShowForm("Hide older videos", "Hide", "Cancel", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		age := hideOlderChoices[period.SelectedIndex()].age
+		hidden, err := video.HidePublishedBefore(time.Now().Add(-age))
+		if err != nil {
+			dialog.ShowError(err, f.window)
+			return
+		}
+		f.load()
+		dialog.ShowInformation("Hide older videos", fmt.Sprintf("Hid %d videos, queued ones are kept.", hidden), f.window)
+	}, f.window)
+}
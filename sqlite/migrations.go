@@ -0,0 +1,9 @@
+package sqlite
+
+import "embed"
+
+// Migrations holds the schema changes in the order they are applied.
+// They are also the schema sqlc generates the database package from.
+//
+//go:embed migrations/*.sql
+var Migrations embed.FS
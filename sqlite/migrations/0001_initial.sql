@@ -1,4 +1,4 @@
-CREATE TABLE videos (
+CREATE TABLE IF NOT EXISTS videos (
 	video_id TEXT PRIMARY KEY,
 	title TEXT,
 	thumbnail_url TEXT,
@@ -12,7 +12,7 @@ CREATE TABLE videos (
 	is_hidden INTEGER
 );
 
-CREATE TABLE thumbnails (
+CREATE TABLE IF NOT EXISTS thumbnails (
 	video_id TEXT PRIMARY KEY,
 	thumbnail BLOB,
 	updated_at TEXT,
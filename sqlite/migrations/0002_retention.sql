@@ -0,0 +1,17 @@
+ALTER TABLE videos ADD COLUMN watched_at TEXT;
+ALTER TABLE videos ADD COLUMN queued_at TEXT;
+
+CREATE TABLE archived_videos (
+	video_id TEXT PRIMARY KEY,
+	title TEXT,
+	thumbnail_url TEXT,
+	channel_name TEXT,
+	description TEXT,
+	published_at TEXT,
+	hours INTEGER,
+	minutes INTEGER,
+	seconds INTEGER,
+	was_live INTEGER,
+	is_hidden INTEGER,
+	archived_at TEXT
+);
@@ -0,0 +1,7 @@
+ALTER TABLE videos ADD COLUMN fetched_at TEXT;
+UPDATE videos SET fetched_at = published_at;
+
+CREATE TABLE app_state (
+	key TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+);
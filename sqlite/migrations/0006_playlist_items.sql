@@ -0,0 +1,9 @@
+CREATE TABLE playlist_items (
+	playlist_id TEXT NOT NULL,
+	video_id TEXT NOT NULL,
+	added_at TEXT NOT NULL,
+	PRIMARY KEY (playlist_id, video_id),
+	FOREIGN KEY(video_id) REFERENCES videos(video_id) ON DELETE CASCADE
+);
+
+CREATE INDEX playlist_items_added_at ON playlist_items (playlist_id, added_at);
@@ -0,0 +1,22 @@
+-- video_sources replaces playlist_items, recording every feed a video was
+-- fetched from. feed_type is uploads, live, shorts or playlist, added_at is
+-- only set for playlists.
+CREATE TABLE video_sources (
+	video_id TEXT NOT NULL,
+	feed_id TEXT NOT NULL,
+	feed_type TEXT NOT NULL,
+	feed_name TEXT NOT NULL,
+	first_seen TEXT NOT NULL,
+	added_at TEXT,
+	PRIMARY KEY (video_id, feed_id, feed_type),
+	FOREIGN KEY(video_id) REFERENCES videos(video_id) ON DELETE CASCADE
+);
+
+CREATE INDEX video_sources_feed ON video_sources (feed_id, added_at);
+
+INSERT INTO video_sources (video_id, feed_id, feed_type, feed_name, first_seen, added_at)
+SELECT playlist_items.video_id, playlist_items.playlist_id, 'playlist', '', coalesce(videos.fetched_at, playlist_items.added_at), playlist_items.added_at
+FROM playlist_items
+JOIN videos ON videos.video_id = playlist_items.video_id;
+
+DROP TABLE playlist_items;
@@ -0,0 +1,6 @@
+-- video_type is normal, short or live. Shorts are only known by the feed of a
+-- channel's Shorts they were fetched from, live streams and premieres by was_live.
+ALTER TABLE videos ADD COLUMN video_type TEXT NOT NULL DEFAULT 'normal';
+UPDATE videos SET video_type = 'live' WHERE was_live = 1;
+UPDATE videos SET video_type = 'short'
+WHERE video_id IN (SELECT video_id FROM video_sources WHERE feed_type = 'shorts');
@@ -1,5 +1,5 @@
 -- name: FetchVideos :many
-select video_id, title, thumbnail_url, channel_name, description, published_at, hours, minutes, seconds, was_live, is_hidden
+select video_id, title, thumbnail_url, channel_name, description, published_at, hours, minutes, seconds, was_live, is_hidden, watched_at, queued_at, categories, position, fetched_at, video_type
 from videos
 where is_hidden = 0
 order by published_at desc
@@ -10,6 +10,22 @@ update videos
 set is_hidden = 1
 where video_id = ?;
 
+-- name: AddVideo :exec
+INSERT INTO videos (video_id, title, thumbnail_url, channel_name, description, published_at, hours, minutes, seconds, was_live, categories, video_type, is_hidden, fetched_at)
+    VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 0, CURRENT_TIMESTAMP)
+    ON CONFLICT(video_id) DO UPDATE SET
+	title = excluded.title,
+	thumbnail_url = excluded.thumbnail_url,
+	channel_name = excluded.channel_name,
+	description = excluded.description,
+	published_at = excluded.published_at,
+	hours = excluded.hours,
+	minutes = excluded.minutes,
+	seconds = excluded.seconds,
+	was_live = excluded.was_live,
+	categories = excluded.categories,
+	video_type = case videos.video_type when 'short' then 'short' else excluded.video_type end;
+
 -- name: AddThumbnail :exec
 INSERT INTO thumbnails(video_id, thumbnail, updated_at)
 VALUES (?, ?, CURRENT_TIMESTAMP)
@@ -19,3 +35,204 @@ ON CONFLICT(video_id) DO UPDATE SET
 
 -- name: FetchThumbnail :one
 SELECT thumbnail FROM thumbnails WHERE video_id = ?;
+
+-- name: FetchVideosForThumbnails :many
+select v.video_id, v.thumbnail_url, cast(coalesce(length(t.thumbnail), 0) as integer) as size
+from videos v
+left join thumbnails t on t.video_id = v.video_id
+where v.is_hidden = 0
+and (v.published_at >= sqlc.arg(published_at) or v.fetched_at >= sqlc.arg(published_at))
+order by v.published_at desc;
+
+-- name: DeleteHiddenThumbnails :execrows
+delete from thumbnails
+where video_id in (select video_id from videos where is_hidden = 1);
+
+-- name: DeleteThumbnailsPublishedBefore :execrows
+delete from thumbnails
+where video_id in (select video_id from videos where published_at < sqlc.arg(published_at) and fetched_at < sqlc.arg(published_at));
+
+-- name: FetchThumbnailSizes :many
+select t.video_id, length(t.thumbnail) as size
+from thumbnails t
+join videos v on v.video_id = t.video_id
+order by v.published_at desc;
+
+-- name: DeleteThumbnail :exec
+delete from thumbnails where video_id = ?;
+
+-- name: ArchiveExpiredVideos :execrows
+insert into archived_videos (video_id, title, thumbnail_url, channel_name, description, published_at, hours, minutes, seconds, was_live, is_hidden, archived_at)
+select video_id, title, thumbnail_url, channel_name, description, published_at, hours, minutes, seconds, was_live, is_hidden, CURRENT_TIMESTAMP
+from videos
+where published_at < sqlc.arg(published_at)
+and fetched_at < sqlc.arg(published_at)
+and watched_at is null
+and queued_at is null
+on conflict(video_id) do nothing;
+
+-- name: DeleteExpiredVideos :execrows
+delete from videos
+where published_at < sqlc.arg(published_at)
+and fetched_at < sqlc.arg(published_at)
+and watched_at is null
+and queued_at is null;
+
+-- name: IsVideoArchived :one
+select exists(select 1 from archived_videos where video_id = ?);
+
+-- name: IsVideoStored :one
+select exists(select 1 from videos where video_id = ?);
+
+-- name: FetchVideoCategories :one
+select categories from videos where video_id = ?;
+
+-- name: SetVideoPosition :exec
+update videos
+set position = ?
+where video_id = ?;
+
+-- name: FetchQueuedVideos :many
+select video_id, title, thumbnail_url, channel_name, description, published_at, hours, minutes, seconds, was_live, is_hidden, watched_at, queued_at, categories, position, fetched_at, video_type
+from videos
+where queued_at is not null
+and is_hidden = 0
+order by queued_at;
+
+-- name: QueueVideo :exec
+update videos
+set queued_at = coalesce(queued_at, CURRENT_TIMESTAMP)
+where video_id = ?;
+
+-- name: UnqueueVideo :exec
+update videos
+set queued_at = null
+where video_id = ?;
+
+-- name: FilterVideos :many
+select video_id, title, thumbnail_url, channel_name, description, published_at, hours, minutes, seconds, was_live, is_hidden, watched_at, queued_at, categories, position, fetched_at, video_type
+from videos
+where is_hidden = 0
+and (title like sqlc.arg(pattern) escape '\' or channel_name like sqlc.arg(pattern) escape '\')
+and (cast(sqlc.arg(category) as text) = '' or instr(',' || categories || ',', ',' || sqlc.arg(category) || ',') > 0)
+and (cast(sqlc.arg(unwatched) as integer) = 0 or watched_at is null)
+and (cast(sqlc.arg(hide_live) as integer) = 0 or was_live = 0)
+and (cast(sqlc.arg(source) as text) = '' or exists (
+	select 1 from video_sources
+	where video_sources.video_id = videos.video_id
+	and video_sources.feed_id = sqlc.arg(source)
+))
+and (cast(sqlc.arg(video_type) as text) = '' or video_type = sqlc.arg(video_type))
+and (cast(sqlc.arg(hide_shorts) as integer) = 0 or video_type != 'short')
+order by
+	case cast(sqlc.arg(sort) as text) when 'oldest' then published_at end,
+	case sqlc.arg(sort) when 'longest' then hours * 3600 + minutes * 60 + seconds end desc,
+	case sqlc.arg(sort) when 'shortest' then hours * 3600 + minutes * 60 + seconds end,
+	published_at desc
+limit sqlc.arg(max_videos);
+
+-- name: FetchCategories :many
+select distinct categories from videos
+where is_hidden = 0
+and categories != '';
+
+-- name: MarkVideoWatched :exec
+update videos
+set watched_at = CURRENT_TIMESTAMP
+where video_id = ?;
+
+-- name: MarkVideoUnwatched :exec
+update videos
+set watched_at = null
+where video_id = ?;
+
+-- name: HideVideosPublishedBefore :execrows
+update videos
+set is_hidden = 1
+where is_hidden = 0
+and queued_at is null
+and published_at < ?;
+
+-- name: FetchState :one
+select value from app_state
+where key = ?;
+
+-- name: SetState :exec
+insert into app_state (key, value)
+values (?, ?)
+on conflict(key) do update set value = excluded.value;
+
+-- name: CountVideosSince :one
+select count(*) from videos
+where is_hidden = 0
+and (published_at > sqlc.arg(since) or fetched_at > sqlc.arg(since))
+and (cast(sqlc.arg(hide_shorts) as integer) = 0 or video_type != 'short');
+
+-- name: FetchHistory :many
+select video_id, title, thumbnail_url, channel_name, description, published_at, hours, minutes, seconds, was_live, is_hidden, watched_at, queued_at, categories, position, fetched_at, video_type
+from videos
+where published_at >= sqlc.arg(published_from)
+and published_at < sqlc.arg(published_until)
+and (cast(sqlc.arg(category) as text) = '' or instr(',' || categories || ',', ',' || sqlc.arg(category) || ',') > 0)
+order by published_at;
+
+-- name: AddPlaceholderVideo :execrows
+insert into videos (video_id, title, thumbnail_url, channel_name, description, published_at, hours, minutes, seconds, was_live, categories, is_hidden, fetched_at, video_type)
+values (?, ?, ?, ?, '', ?, ?, ?, ?, 0, '', 0, ?, ?)
+on conflict(video_id) do nothing;
+
+-- name: MarkVideoWatchedAt :execrows
+update videos
+set watched_at = ?
+where video_id = ?
+and watched_at is null;
+
+-- name: QueueVideoAt :execrows
+update videos
+set queued_at = ?
+where video_id = ?
+and queued_at is null;
+
+-- name: AddVideoSource :exec
+insert into video_sources (video_id, feed_id, feed_type, feed_name, first_seen, added_at)
+values (?, ?, ?, ?, CURRENT_TIMESTAMP, ?)
+on conflict(video_id, feed_id, feed_type) do update set
+	feed_name = excluded.feed_name,
+	added_at = excluded.added_at;
+
+-- name: FetchVideoSources :many
+select video_id, feed_id, feed_type, feed_name, first_seen, added_at
+from video_sources
+where video_id = ?
+order by first_seen;
+
+-- name: FetchFeeds :many
+select distinct video_sources.feed_id, video_sources.feed_name
+from video_sources
+join videos on videos.video_id = video_sources.video_id
+where videos.is_hidden = 0
+and video_sources.feed_name != ''
+order by video_sources.feed_name;
+
+-- name: RenameFeed :exec
+update video_sources
+set feed_name = sqlc.arg(feed_name)
+where feed_id = sqlc.arg(feed_id)
+and feed_name != sqlc.arg(feed_name);
+
+-- name: FetchPlaylistItems :many
+select video_id, added_at
+from video_sources
+where feed_id = sqlc.arg(feed_id)
+and feed_type = 'playlist'
+order by added_at desc
+limit sqlc.arg(max_items);
+
+-- name: FetchPlaylistVideos :many
+select sqlc.embed(videos), video_sources.added_at
+from videos
+join video_sources on video_sources.video_id = videos.video_id
+where video_sources.feed_id = ?
+and video_sources.feed_type = 'playlist'
+and videos.is_hidden = 0
+order by video_sources.added_at desc;
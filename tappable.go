@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+// tappable wraps a canvas object to react to taps on it. Tappable widgets
+// inside, like buttons, still receive their own taps.
+type tappable struct {
+	widget.BaseWidget
+	content  fyne.CanvasObject
+	onTapped func(modifier fyne.KeyModifier)
+
+	// modifier holds the keys pressed with the mouse button, taps don't carry them.
+	modifier fyne.KeyModifier
+}
+
+func newTappable(content fyne.CanvasObject, onTapped func(modifier fyne.KeyModifier)) *tappable {
+	t := &tappable{content: content, onTapped: onTapped}
+	t.ExtendBaseWidget(t)
+	return t
+}
+
+func (t *tappable) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(t.content)
+}
+
+func (t *tappable) MouseDown(event *desktop.MouseEvent) {
+	t.modifier = event.Modifier
+}
+
+func (t *tappable) MouseUp(*desktop.MouseEvent) {}
+
+func (t *tappable) Tapped(*fyne.PointEvent) {
+	t.onTapped(t.modifier)
+	t.modifier = 0
+}
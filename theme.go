@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	themeSystem = "System"
+	themeLight  = "Light"
+	themeDark   = "Dark"
+)
+
+var themeChoices = []string{themeSystem, themeLight, themeDark}
+
+// colorNameLive is the color of the LIVE label on cards.
+const colorNameLive fyne.ThemeColorName = "live"
+
+// deeptubeTheme is Fyne's default theme with a fixed or system variant and
+// an optional accent color.
+type deeptubeTheme struct {
+	choice string
+	// accent replaces the primary color, nil keeps Fyne's.
+	accent color.Color
+}
+
+func newTheme(prefs fyne.Preferences) *deeptubeTheme {
+	t := &deeptubeTheme{choice: prefs.StringWithFallback(prefTheme, themeSystem)}
+	accent, err := parseHexColor(prefs.String(prefAccent))
+	if err == nil {
+		t.accent = accent
+	}
+	return t
+}
+
+func (t *deeptubeTheme) Color(name fyne.ThemeColorName, variant fyne.ThemeVariant) color.Color {
+	switch t.choice {
+	case themeLight:
+		variant = theme.VariantLight
+	case themeDark:
+		variant = theme.VariantDark
+	}
+
+	switch name {
+	case colorNameLive:
+		if variant == theme.VariantDark {
+			return color.NRGBA{R: 0xff, G: 0x4e, B: 0x45, A: 0xff}
+		}
+		return color.NRGBA{R: 0xcc, A: 0xff}
+	case theme.ColorNamePrimary:
+		if t.accent != nil {
+			return t.accent
+		}
+	case theme.ColorNameFocus:
+		if t.accent != nil {
+			return withAlpha(t.accent, 0x7f)
+		}
+	case theme.ColorNameSelection:
+		if t.accent != nil {
+			return withAlpha(t.accent, 0x3f)
+		}
+	}
+	return theme.DefaultTheme().Color(name, variant)
+}
+
+func (t *deeptubeTheme) Font(style fyne.TextStyle) fyne.Resource {
+	return theme.DefaultTheme().Font(style)
+}
+
+func (t *deeptubeTheme) Icon(name fyne.ThemeIconName) fyne.Resource {
+	return theme.DefaultTheme().Icon(name)
+}
+
+func (t *deeptubeTheme) Size(name fyne.ThemeSizeName) float32 {
+	return theme.DefaultTheme().Size(name)
+}
+
+func withAlpha(c color.Color, alpha uint8) color.Color {
+	nrgba := color.NRGBAModel.Convert(c).(color.NRGBA)
+	nrgba.A = alpha
+	return nrgba
+}
+
+// parseHexColor parses colors like "#ff8800".
+func parseHexColor(hex string) (color.Color, error) {
+	var c color.NRGBA
+	_, err := fmt.Sscanf(hex, "#%02x%02x%02x", &c.R, &c.G, &c.B)
+	if err != nil {
+		return nil, fmt.Errorf("invalid color %q: %w", hex, err)
+	}
+	c.A = 0xff
+	return c, nil
+}
+
+func formatHexColor(c color.Color) string {
+	nrgba := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return fmt.Sprintf("#%02x%02x%02x", nrgba.R, nrgba.G, nrgba.B)
+}
+
+// showThemeDialog lets the user pick the theme variant and accent color,
+// changes apply immediately and are stored with the other preferences.
+func (f *feed) showThemeDialog() {
+	a := fyne.CurrentApp()
+	apply := func() {
+		a.Settings().SetTheme(newTheme(f.prefs))
+	}
+
+	variantSelect := widget.NewSelect(themeChoices, func(choice string) {
+		f.prefs.SetString(prefTheme, choice)
+		apply()
+	})
+	variantSelect.Selected = f.prefs.StringWithFallback(prefTheme, themeSystem)
+
+	accentBtn := widget.NewButtonWithIcon("Choose", theme.ColorPaletteIcon(), func() {
+		picker := dialog.NewColorPicker("Accent color", "", func(c color.Color) {
+			f.prefs.SetString(prefAccent, formatHexColor(c))
+			apply()
+		}, f.window)
+		picker.Advanced = true
+		picker.Show()
+	})
+	resetBtn := widget.NewButton("Default", func() {
+		f.prefs.RemoveValue(prefAccent)
+		apply()
+	})
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Theme", variantSelect),
+		widget.NewFormItem("Accent color", container.NewHBox(accentBtn, resetBtn)),
+	}
+	dialog.ShowCustom("Appearance", "Close", widget.NewForm(items...), f.window)
+}
@@ -0,0 +1,78 @@
+package thumbnail
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/aaronzipp/deeptube/config"
+	"github.com/aaronzipp/deeptube/database"
+)
+
+const bytesInMB = 1024 * 1024
+
+// Cutoff returns the time before which videos published and fetched don't
+// keep thumbnails.
+func Cutoff(settings config.Thumbnails) time.Time {
+	if settings.MaxAgeDays == 0 {
+		return time.Time{}
+	}
+	return time.Now().AddDate(0, 0, -settings.MaxAgeDays)
+}
+
+// Prune removes the thumbnails of hidden videos and of videos published and
+// fetched more than MaxAgeDays ago. If the rest still exceeds MaxSizeMB the thumbnails of
+// the oldest videos go first. It returns the number of removed thumbnails.
+func Prune(settings config.Thumbnails) (int64, error) {
+	ctx := context.Background()
+	db, err := database.Open()
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	queries := database.New(tx)
+
+	removed, err := queries.DeleteHiddenThumbnails(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if settings.MaxAgeDays > 0 {
+		count, err := queries.DeleteThumbnailsPublishedBefore(ctx, sql.NullString{
+			String: Cutoff(settings).UTC().Format("2006-01-02 15:04:05"),
+			Valid:  true,
+		})
+		if err != nil {
+			return 0, err
+		}
+		removed += count
+	}
+
+	if settings.MaxSizeMB > 0 {
+		sizes, err := queries.FetchThumbnailSizes(ctx)
+		if err != nil {
+			return 0, err
+		}
+
+		var total int64
+		for _, thumbnail := range sizes {
+			total += thumbnail.Size
+			if total <= int64(settings.MaxSizeMB)*bytesInMB {
+				continue
+			}
+			err = queries.DeleteThumbnail(ctx, thumbnail.VideoID)
+			if err != nil {
+				return 0, err
+			}
+			removed++
+		}
+	}
+
+	return removed, tx.Commit()
+}
@@ -0,0 +1,261 @@
+package thumbnail
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"image"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aaronzipp/deeptube/config"
+	"github.com/aaronzipp/deeptube/database"
+
+	"golang.org/x/image/draw"
+)
+
+// Thumbnails are stored at twice the card size so they stay sharp on HiDPI
+// screens while still being a fraction of YouTube's full resolution images.
+const (
+	Width  = 416
+	Height = 234
+)
+
+const maxAttempts = 3
+const retryDelay = 2 * time.Second
+const queueSize = 1024
+
+type request struct {
+	videoID string
+	url     string
+}
+
+// Queue downloads, resizes and stores thumbnails in the background.
+type Queue struct {
+	workers  int
+	requests chan request
+
+	mu      sync.Mutex
+	pending map[string]bool
+	onReady []func(videoID string, data []byte)
+}
+
+func NewQueue(workers int) *Queue {
+	return &Queue{
+		workers:  workers,
+		requests: make(chan request, queueSize),
+		pending:  make(map[string]bool),
+	}
+}
+
+// OnReady registers a callback that is called from a worker goroutine
+// whenever a thumbnail has been stored.
+func (q *Queue) OnReady(callback func(videoID string, data []byte)) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.onReady = append(q.onReady, callback)
+}
+
+func (q *Queue) Start() error {
+	db, err := database.Open()
+	if err != nil {
+		return err
+	}
+	queries := database.New(db)
+
+	for range q.workers {
+		go func() {
+			for req := range q.requests {
+				q.process(queries, req)
+			}
+		}()
+	}
+	return nil
+}
+
+// Enqueue schedules a thumbnail download unless one for the video is already
+// pending. It never blocks; when the queue is full the request is dropped and
+// picked up again by the next EnqueueMissing.
+func (q *Queue) Enqueue(videoID, url string) {
+	if url == "" {
+		return
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.pending[videoID] {
+		return
+	}
+
+	select {
+	case q.requests <- request{videoID: videoID, url: url}:
+		q.pending[videoID] = true
+	default:
+	}
+}
+
+// EnqueueMissing schedules downloads for the visible videos within the limits
+// of settings that don't have a thumbnail. Like Prune it keeps to MaxSizeMB
+// with the newest videos, so pruned thumbnails aren't downloaded again.
+func (q *Queue) EnqueueMissing(settings config.Thumbnails) error {
+	ctx := context.Background()
+	db, err := database.Open()
+	if err != nil {
+		return err
+	}
+
+	queries := database.New(db)
+
+	vids, err := queries.FetchVideosForThumbnails(ctx, sql.NullString{
+		String: Cutoff(settings).UTC().Format("2006-01-02 15:04:05"),
+		Valid:  true,
+	})
+	if err != nil {
+		return err
+	}
+	for _, vid := range missing(vids, int64(settings.MaxSizeMB)*bytesInMB) {
+		q.Enqueue(vid.VideoID, vid.ThumbnailUrl.String)
+	}
+	return nil
+}
+
+// estimatedSize is assumed for a missing thumbnail while none are stored.
+const estimatedSize = 32 * 1024
+
+// missing returns the videos without a thumbnail among the newest ones whose
+// thumbnails fit into budget, zero meaning no limit. vids are ordered newest
+// first, missing thumbnails are assumed to be as large as the stored ones on
+// average.
+func missing(vids []database.FetchVideosForThumbnailsRow, budget int64) []database.FetchVideosForThumbnailsRow {
+	var stored, storedSize int64
+	for _, vid := range vids {
+		if vid.Size > 0 {
+			stored++
+			storedSize += vid.Size
+		}
+	}
+	estimate := int64(estimatedSize)
+	if stored > 0 {
+		estimate = storedSize / stored
+	}
+
+	var result []database.FetchVideosForThumbnailsRow
+	var total int64
+	for _, vid := range vids {
+		if vid.Size == 0 && vid.ThumbnailUrl.String == "" {
+			continue
+		}
+		size := vid.Size
+		if size == 0 {
+			size = estimate
+		}
+		total += size
+		if budget > 0 && total > budget {
+			break
+		}
+		if vid.Size == 0 {
+			result = append(result, vid)
+		}
+	}
+	return result
+}
+
+func (q *Queue) process(queries *database.Queries, req request) {
+	defer func() {
+		q.mu.Lock()
+		delete(q.pending, req.videoID)
+		q.mu.Unlock()
+	}()
+
+	ctx := context.Background()
+
+	var data []byte
+	var err error
+	for attempt := range maxAttempts {
+		if attempt > 0 {
+			time.Sleep(retryDelay << (attempt - 1))
+		}
+		data, err = download(req.url)
+		if err == nil {
+			break
+		}
+	}
+	if err != nil {
+		log.Printf("failed downloading thumbnail of %s: %s", req.videoID, err)
+		return
+	}
+
+	err = queries.AddThumbnail(ctx, database.AddThumbnailParams{
+		VideoID:   req.videoID,
+		Thumbnail: data,
+	})
+	if err != nil {
+		log.Printf("failed storing thumbnail of %s: %s", req.videoID, err)
+		return
+	}
+
+	q.mu.Lock()
+	callbacks := q.onReady
+	q.mu.Unlock()
+	for _, callback := range callbacks {
+		callback(req.videoID, data)
+	}
+}
+
+func download(url string) ([]byte, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download thumbnail: %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return Resize(data)
+}
+
+// Resize crops an image to 16:9 and scales it down to the card resolution,
+// returning it JPEG encoded.
+func Resize(data []byte) ([]byte, error) {
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, Width, Height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, crop(src.Bounds()), draw.Src, nil)
+
+	var buf bytes.Buffer
+	err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 85})
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// crop returns the centered 16:9 part of bounds. YouTube serves most
+// thumbnails as 4:3 images with black bars above and below the video.
+func crop(bounds image.Rectangle) image.Rectangle {
+	width, height := bounds.Dx(), bounds.Dy()
+	if width*Height > height*Width {
+		cropped := height * Width / Height
+		offset := (width - cropped) / 2
+		return image.Rect(bounds.Min.X+offset, bounds.Min.Y, bounds.Min.X+offset+cropped, bounds.Max.Y)
+	}
+	cropped := width * Height / Width
+	offset := (height - cropped) / 2
+	return image.Rect(bounds.Min.X, bounds.Min.Y+offset, bounds.Max.X, bounds.Min.Y+offset+cropped)
+}
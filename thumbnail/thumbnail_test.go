@@ -0,0 +1,90 @@
+package thumbnail
+
+import (
+	"bytes"
+	"database/sql"
+	"image"
+	"image/jpeg"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/aaronzipp/deeptube/database"
+)
+
+func TestCrop(t *testing.T) {
+	testData := []struct {
+		input  image.Rectangle
+		output image.Rectangle
+	}{
+		{input: image.Rect(0, 0, 640, 480), output: image.Rect(0, 60, 640, 420)},
+		{input: image.Rect(0, 0, 1280, 720), output: image.Rect(0, 0, 1280, 720)},
+		{input: image.Rect(0, 0, 720, 360), output: image.Rect(40, 0, 680, 360)},
+	}
+
+	for _, tt := range testData {
+		t.Run(tt.input.String(), func(t *testing.T) {
+			got := crop(tt.input)
+
+			if got != tt.output {
+				t.Errorf("Got %s, want %s", got, tt.output)
+			}
+		})
+	}
+}
+
+func TestResize(t *testing.T) {
+	var buf bytes.Buffer
+	err := jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 640, 480)), nil)
+	if err != nil {
+		t.Fatalf("Got an unexpected error: %q", err)
+	}
+
+	data, err := Resize(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Got an unexpected error: %q", err)
+	}
+
+	got, err := jpeg.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Got an unexpected error: %q", err)
+	}
+	if got.Width != Width || got.Height != Height {
+		t.Errorf("Got %dx%d, want %dx%d", got.Width, got.Height, Width, Height)
+	}
+}
+
+func TestMissing(t *testing.T) {
+	url := sql.NullString{String: "https://i.ytimg.com/vi/x/hqdefault.jpg", Valid: true}
+	vids := []database.FetchVideosForThumbnailsRow{
+		{VideoID: "new", ThumbnailUrl: url},
+		{VideoID: "stored", ThumbnailUrl: url, Size: 100},
+		{VideoID: "no thumbnail"},
+		{VideoID: "missing", ThumbnailUrl: url},
+		{VideoID: "old", ThumbnailUrl: url, Size: 300},
+		{VideoID: "oldest", ThumbnailUrl: url},
+	}
+
+	testData := []struct {
+		budget int64
+		output []string
+	}{
+		{0, []string{"new", "missing", "oldest"}},
+		{500, []string{"new", "missing"}},
+		{200, []string{"new"}},
+		{100, nil},
+	}
+
+	for _, tt := range testData {
+		t.Run(strconv.FormatInt(tt.budget, 10), func(t *testing.T) {
+			var got []string
+			for _, vid := range missing(vids, tt.budget) {
+				got = append(got, vid.VideoID)
+			}
+
+			if !reflect.DeepEqual(got, tt.output) {
+				t.Errorf("Got %v, want %v", got, tt.output)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package video
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/aaronzipp/deeptube/database"
+)
+
+// Hide hides all videos in a single transaction.
+func (v Videos) Hide() error {
+	return v.update(func(ctx context.Context, queries *database.Queries, videoID string) error {
+		return queries.HideVideo(ctx, videoID)
+	})
+}
+
+// MarkWatched marks all videos as watched in a single transaction.
+func (v Videos) MarkWatched() error {
+	return v.update(func(ctx context.Context, queries *database.Queries, videoID string) error {
+		return queries.MarkVideoWatched(ctx, videoID)
+	})
+}
+
+// Queue adds all videos to the queue in a single transaction. Videos that
+// are queued already keep their place.
+func (v Videos) Queue() error {
+	return v.update(func(ctx context.Context, queries *database.Queries, videoID string) error {
+		return queries.QueueVideo(ctx, videoID)
+	})
+}
+
+func (v Videos) update(apply func(context.Context, *database.Queries, string) error) error {
+	ctx := context.Background()
+	db, err := database.Open()
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	queries := database.New(tx)
+	for _, vid := range v {
+		err = apply(ctx, queries, vid.VideoId)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// HidePublishedBefore hides every video published before t, except for the
+// queued ones, and returns how many were hidden.
+func HidePublishedBefore(t time.Time) (int64, error) {
+	ctx := context.Background()
+	db, err := database.Open()
+	if err != nil {
+		return 0, err
+	}
+
+	queries := database.New(db)
+
+	return queries.HideVideosPublishedBefore(ctx, sql.NullString{
+		String: t.UTC().Format("2006-01-02 15:04:05"),
+		Valid:  true,
+	})
+}
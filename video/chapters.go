@@ -0,0 +1,54 @@
+package video
+
+import (
+	"regexp"
+	"strings"
+)
+
+type Chapter struct {
+	Start int
+	Title string
+}
+
+// A chapter line starts or ends with its timestamp, e.g. "0:00 Intro",
+// "(12:34) - Results" or "Outro 1:02:03".
+var (
+	leadingTimestamp  = regexp.MustCompile(`^[(\[]?((?:\d+:)?\d{1,2}:\d{2})[)\]]?\s*[-–—:|]?\s*(.+)$`)
+	trailingTimestamp = regexp.MustCompile(`^(.+?)\s*[-–—:|]?\s*[(\[]?((?:\d+:)?\d{1,2}:\d{2})[)\]]?$`)
+)
+
+// ParseChapters extracts the chapters from a video description. Like YouTube
+// it only accepts them if there are at least two, the first one starts at
+// 0:00 and they are in ascending order.
+func ParseChapters(description string) []Chapter {
+	var chapters []Chapter
+
+	for _, line := range strings.Split(description, "\n") {
+		line = strings.TrimLeft(strings.TrimSpace(line), "-•*▶ ")
+
+		var timestamp, title string
+		if matches := leadingTimestamp.FindStringSubmatch(line); matches != nil {
+			timestamp, title = matches[1], matches[2]
+		} else if matches := trailingTimestamp.FindStringSubmatch(line); matches != nil {
+			title, timestamp = matches[1], matches[2]
+		} else {
+			continue
+		}
+
+		start, err := ParseTimestamp(timestamp)
+		if err != nil {
+			continue
+		}
+		chapters = append(chapters, Chapter{Start: start.TotalSeconds(), Title: title})
+	}
+
+	if len(chapters) < 2 || chapters[0].Start != 0 {
+		return nil
+	}
+	for i := 1; i < len(chapters); i++ {
+		if chapters[i].Start <= chapters[i-1].Start {
+			return nil
+		}
+	}
+	return chapters
+}
@@ -0,0 +1,47 @@
+package video
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseChapters(t *testing.T) {
+	description := `Today we look at parsers.
+
+Chapters:
+0:00 Intro
+(2:05) - Lexing
+- 12:30 Parsing
+Outro 1:01:00
+
+Links: https://example.com`
+
+	got := ParseChapters(description)
+	want := []Chapter{
+		{Start: 0, Title: "Intro"},
+		{Start: 125, Title: "Lexing"},
+		{Start: 750, Title: "Parsing"},
+		{Start: 3660, Title: "Outro"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %+v, want %+v", got, want)
+	}
+}
+
+func TestParseChaptersInvalid(t *testing.T) {
+	testData := []string{
+		"No chapters here",
+		"1:00 Starts late\n2:00 Second",
+		"0:00 Intro\n5:00 Later\n3:00 Earlier",
+		"0:00 Only one",
+	}
+
+	for _, description := range testData {
+		got := ParseChapters(description)
+
+		if got != nil {
+			t.Errorf("Got %+v for %q, want no chapters", got, description)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package video
+
+import "sync"
+
+var (
+	listenersMu    sync.Mutex
+	addedListeners []func(Videos)
+)
+
+// OnAdded registers a listener that is called with the new videos whenever
+// WriteToDB stored some. Listeners are called on the goroutine writing them.
+func OnAdded(listener func(added Videos)) {
+	listenersMu.Lock()
+	defer listenersMu.Unlock()
+	addedListeners = append(addedListeners, listener)
+}
+
+func notifyAdded(added Videos) {
+	listenersMu.Lock()
+	listeners := addedListeners
+	listenersMu.Unlock()
+
+	for _, listener := range listeners {
+		listener(added)
+	}
+}
@@ -0,0 +1,150 @@
+package video
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"strings"
+
+	"github.com/aaronzipp/deeptube/database"
+)
+
+// Order is the order videos are listed in.
+type Order string
+
+const (
+	Newest   Order = "newest"
+	Oldest   Order = "oldest"
+	Longest  Order = "longest"
+	Shortest Order = "shortest"
+)
+
+var Orders = []Order{Newest, Oldest, Longest, Shortest}
+
+// Filter narrows down the videos shown. The zero value shows all videos, newest first.
+type Filter struct {
+	// Query has to be part of the title or channel, ignoring case.
+	Query    string
+	Category string
+	// Unwatched hides the watched videos.
+	Unwatched bool
+	// NoLive hides past live streams.
+	NoLive bool
+	// Source limits the videos to those fetched from the feed with this ID.
+	Source string
+	// Type limits the videos to one type, empty for all.
+	Type VideoType
+	// NoShorts hides Shorts.
+	NoShorts bool
+	Order    Order
+}
+
+// Matches reports whether vid passes the filter.
+func (f Filter) Matches(vid Video) bool {
+	query := strings.ToLower(f.Query)
+	if !strings.Contains(strings.ToLower(vid.Title), query) &&
+		!strings.Contains(strings.ToLower(vid.ChannelName), query) {
+		return false
+	}
+	if f.Category != "" && !slices.Contains(vid.Categories, f.Category) {
+		return false
+	}
+	if f.Unwatched && vid.Watched {
+		return false
+	}
+	if f.Source != "" && !vid.HasSource(f.Source) {
+		return false
+	}
+	if f.Type != "" && vid.Type != f.Type {
+		return false
+	}
+	if f.NoShorts && vid.Type == ShortVideo {
+		return false
+	}
+	return !f.NoLive || !vid.WasLive
+}
+
+// Filter returns the videos passing filter, keeping their order.
+func (v Videos) Filter(filter Filter) Videos {
+	matches := Videos{}
+	for _, vid := range v {
+		if filter.Matches(vid) {
+			matches = append(matches, vid)
+		}
+	}
+	return matches
+}
+
+// FilteredVideosFromDB returns up to limit videos passing filter in its order.
+func FilteredVideosFromDB(filter Filter, limit int) (Videos, error) {
+	ctx := context.Background()
+	db, err := database.Open()
+
+	if err != nil {
+		return nil, err
+	}
+
+	queries := database.New(db)
+
+	dbVideos, err := queries.FilterVideos(ctx, database.FilterVideosParams{
+		Pattern:    sql.NullString{String: "%" + escapeLike(filter.Query) + "%", Valid: true},
+		Category:   filter.Category,
+		Unwatched:  boolToInt(filter.Unwatched),
+		HideLive:   boolToInt(filter.NoLive),
+		Source:     filter.Source,
+		VideoType:  string(filter.Type),
+		HideShorts: boolToInt(filter.NoShorts),
+		Sort:       string(filter.Order),
+		MaxVideos:  int64(limit),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return fromDB(ctx, queries, dbVideos), nil
+}
+
+// CategoriesFromDB returns the sorted categories of all visible videos.
+func CategoriesFromDB() ([]string, error) {
+	ctx := context.Background()
+	db, err := database.Open()
+
+	if err != nil {
+		return nil, err
+	}
+
+	queries := database.New(db)
+
+	rows, err := queries.FetchCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var categories []string
+	for _, row := range rows {
+		for _, category := range splitCategories(row.String) {
+			if !slices.Contains(categories, category) {
+				categories = append(categories, category)
+			}
+		}
+	}
+	slices.Sort(categories)
+	return categories, nil
+}
+
+// likeEscaper escapes the wildcards of LIKE patterns, with \ as the escape character.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLike makes LIKE match s literally, e.g. a search for 100% doesn't
+// match everything starting with 100.
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+func boolToInt(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,61 @@
+package video
+
+import "testing"
+
+func TestFilterMatches(t *testing.T) {
+	vid := Video{
+		Title:       "Writing a Parser",
+		ChannelName: "Compilers",
+		Categories:  []string{"Tech", "Programming"},
+		Watched:     true,
+		Type:        ShortVideo,
+	}
+
+	testData := []struct {
+		name   string
+		filter Filter
+		output bool
+	}{
+		{"zero value", Filter{}, true},
+		{"title", Filter{Query: "parser"}, true},
+		{"channel", Filter{Query: "COMPILERS"}, true},
+		{"no match", Filter{Query: "cooking"}, false},
+		{"category", Filter{Category: "Programming"}, true},
+		{"other category", Filter{Category: "Music"}, false},
+		{"unwatched", Filter{Unwatched: true}, false},
+		{"no live", Filter{NoLive: true}, true},
+		{"type", Filter{Type: ShortVideo}, true},
+		{"other type", Filter{Type: NormalVideo}, false},
+		{"no shorts", Filter{NoShorts: true}, false},
+	}
+
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.filter.Matches(vid)
+
+			if got != tt.output {
+				t.Errorf("Want %t, got %t", tt.output, got)
+			}
+		})
+	}
+}
+
+func TestEscapeLike(t *testing.T) {
+	testData := []struct {
+		input  string
+		output string
+	}{
+		{"parser", "parser"},
+		{"100%", `100\%`},
+		{"snake_case", `snake\_case`},
+		{`C:\`, `C:\\`},
+	}
+
+	for _, tt := range testData {
+		got := escapeLike(tt.input)
+
+		if got != tt.output {
+			t.Errorf("Got %s for %s, want %s", got, tt.input, tt.output)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"regexp"
 	"strconv"
+	"strings"
 )
 
 type Length struct {
@@ -23,6 +24,41 @@ func (l Length) String() string {
 	return fmt.Sprintf("%ds", l.Seconds)
 }
 
+func (l Length) TotalSeconds() int {
+	return l.Hours*3600 + l.Minutes*60 + l.Seconds
+}
+
+// LengthFromSeconds splits a number of seconds into hours, minutes and seconds.
+func LengthFromSeconds(seconds int) Length {
+	return Length{seconds / 3600, seconds % 3600 / 60, seconds % 60}
+}
+
+// Timestamp formats the length like YouTube does in descriptions, e.g. 1:02:03 or 2:03.
+func (l Length) Timestamp() string {
+	if l.Hours > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", l.Hours, l.Minutes, l.Seconds)
+	}
+	return fmt.Sprintf("%d:%02d", l.Minutes, l.Seconds)
+}
+
+// ParseTimestamp parses timestamps like 1:02:03, 2:03 or a plain number of seconds.
+func ParseTimestamp(timestamp string) (Length, error) {
+	parts := strings.Split(strings.TrimSpace(timestamp), ":")
+	if len(parts) > 3 {
+		return Length{}, fmt.Errorf("invalid timestamp %q", timestamp)
+	}
+
+	seconds := 0
+	for _, part := range parts {
+		value, err := strconv.Atoi(part)
+		if err != nil || value < 0 {
+			return Length{}, fmt.Errorf("invalid timestamp %q", timestamp)
+		}
+		seconds = seconds*60 + value
+	}
+	return LengthFromSeconds(seconds), nil
+}
+
 func LengthFromString(lengthText string) (Length, error) {
 	if lengthText == "P0D" {
 		return Length{0, 0, 0}, nil
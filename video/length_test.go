@@ -40,3 +40,57 @@ func TestString(t *testing.T) {
 		t.Errorf("Got %q, want %q", got, want)
 	}
 }
+
+func TestParseTimestamp(t *testing.T) {
+	testData := []struct {
+		input  string
+		output Length
+	}{
+		{input: "1:02:03", output: Length{Hours: 1, Minutes: 2, Seconds: 3}},
+		{input: "12:34", output: Length{Hours: 0, Minutes: 12, Seconds: 34}},
+		{input: "90", output: Length{Hours: 0, Minutes: 1, Seconds: 30}},
+		{input: "75:00", output: Length{Hours: 1, Minutes: 15, Seconds: 0}},
+	}
+
+	for _, tt := range testData {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseTimestamp(tt.input)
+
+			if err != nil {
+				t.Fatalf("Got an unexpected error: %q", err)
+			}
+
+			if !reflect.DeepEqual(tt.output, got) {
+				t.Errorf("Got %+v, want %+v", got, tt.output)
+			}
+		})
+	}
+}
+
+func TestParseTimestampInvalid(t *testing.T) {
+	for _, input := range []string{"", "1:2:3:4", "ab:12", "-5"} {
+		_, err := ParseTimestamp(input)
+
+		if err == nil {
+			t.Errorf("Expected an error for %q", input)
+		}
+	}
+}
+
+func TestTimestamp(t *testing.T) {
+	testData := []struct {
+		input  Length
+		output string
+	}{
+		{input: Length{1, 2, 3}, output: "1:02:03"},
+		{input: Length{0, 12, 5}, output: "12:05"},
+	}
+
+	for _, tt := range testData {
+		got := tt.input.Timestamp()
+
+		if got != tt.output {
+			t.Errorf("Got %q, want %q", got, tt.output)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package video
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/aaronzipp/deeptube/config"
+	"github.com/aaronzipp/deeptube/database"
+)
+
+// ApplyRetention moves videos that were neither watched nor queued and were
+// published and fetched before the retention period into the archive, old
+// videos just added to a playlist stay. Archived videos are not
+// added again by later refreshes. It returns the number of removed videos.
+func ApplyRetention(settings config.Retention) (int64, error) {
+	if settings.UnseenDays == 0 {
+		return 0, nil
+	}
+
+	ctx := context.Background()
+	db, err := database.Open()
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	queries := database.New(tx)
+
+	cutoff := sql.NullString{
+		String: time.Now().UTC().AddDate(0, 0, -settings.UnseenDays).Format("2006-01-02 15:04:05"),
+		Valid:  true,
+	}
+	_, err = queries.ArchiveExpiredVideos(ctx, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	removed, err := queries.DeleteExpiredVideos(ctx, cutoff)
+	if err != nil {
+		return 0, err
+	}
+
+	return removed, tx.Commit()
+}
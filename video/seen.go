@@ -0,0 +1,101 @@
+package video
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/aaronzipp/deeptube/database"
+)
+
+const (
+	lastSeenKey    = "last_seen"
+	lastRefreshKey = "last_refresh"
+)
+
+// IsNewSince reports whether the video was published or fetched after t.
+// Nothing is new as long as the feed was never seen, i.e. t is zero.
+func (v Video) IsNewSince(t time.Time) bool {
+	if t.IsZero() {
+		return false
+	}
+	return v.PublishedAt.After(t) || v.FetchedAt.After(t)
+}
+
+// LastSeen returns when the feed was last seen, the zero time if it never was.
+func LastSeen() (time.Time, error) {
+	return fetchTime(lastSeenKey)
+}
+
+// SetLastSeen moves the watermark separating new from seen videos to t.
+func SetLastSeen(t time.Time) error {
+	return setTime(lastSeenKey, t)
+}
+
+// LastRefresh returns when the last successful refresh of all feeds started,
+// the zero time if there was none.
+func LastRefresh() (time.Time, error) {
+	return fetchTime(lastRefreshKey)
+}
+
+// SetLastRefresh stores when the refresh of all feeds that just succeeded started.
+func SetLastRefresh(t time.Time) error {
+	return setTime(lastRefreshKey, t)
+}
+
+// fetchTime returns the time stored under key, the zero time if there is none.
+func fetchTime(key string) (time.Time, error) {
+	ctx := context.Background()
+	db, err := database.Open()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	queries := database.New(db)
+
+	value, err := queries.FetchState(ctx, key)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse("2006-01-02 15:04:05", value)
+}
+
+func setTime(key string, t time.Time) error {
+	ctx := context.Background()
+	db, err := database.Open()
+	if err != nil {
+		return err
+	}
+
+	queries := database.New(db)
+
+	return queries.SetState(ctx, database.SetStateParams{
+		Key:   key,
+		Value: t.UTC().Format("2006-01-02 15:04:05"),
+	})
+}
+
+// CountNewSince returns the number of visible videos published or fetched
+// after t, leaving out Shorts if hideShorts is set.
+func CountNewSince(t time.Time, hideShorts bool) (int64, error) {
+	if t.IsZero() {
+		return 0, nil
+	}
+
+	ctx := context.Background()
+	db, err := database.Open()
+	if err != nil {
+		return 0, err
+	}
+
+	queries := database.New(db)
+
+	return queries.CountVideosSince(ctx, database.CountVideosSinceParams{
+		Since:      sql.NullString{String: t.UTC().Format("2006-01-02 15:04:05"), Valid: true},
+		HideShorts: boolToInt(hideShorts),
+	})
+}
@@ -0,0 +1,36 @@
+package video
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIsNewSince(t *testing.T) {
+	lastSeen := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	before := lastSeen.Add(-time.Hour)
+	after := lastSeen.Add(time.Hour)
+
+	testData := []struct {
+		name      string
+		published time.Time
+		fetched   time.Time
+		since     time.Time
+		output    bool
+	}{
+		{"published after", after, after, lastSeen, true},
+		{"fetched after", before, after, lastSeen, true},
+		{"seen", before, before, lastSeen, false},
+		{"never seen", after, after, time.Time{}, false},
+	}
+
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			vid := Video{PublishedAt: tt.published, FetchedAt: tt.fetched}
+			got := vid.IsNewSince(tt.since)
+
+			if got != tt.output {
+				t.Errorf("Want %t, got %t", tt.output, got)
+			}
+		})
+	}
+}
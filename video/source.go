@@ -0,0 +1,171 @@
+package video
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"time"
+
+	"github.com/aaronzipp/deeptube/database"
+)
+
+// FeedType is the kind of feed a video was fetched from.
+type FeedType string
+
+const (
+	UploadsFeed  FeedType = "uploads"
+	LiveFeed     FeedType = "live"
+	ShortsFeed   FeedType = "shorts"
+	PlaylistFeed FeedType = "playlist"
+)
+
+// Source is a subscription or playlist a video was fetched from.
+type Source struct {
+	// FeedId is the ID of the channel or playlist in the config.
+	FeedId string
+	Type   FeedType
+	Name   string
+	// FirstSeen is when the video was first fetched from the feed.
+	FirstSeen time.Time
+	// AddedAt is when the video was added to the playlist, zero for channels.
+	AddedAt time.Time
+}
+
+// Playlists returns the names of the playlists the video was fetched from.
+func (v Video) Playlists() []string {
+	var names []string
+	for _, source := range v.Sources {
+		if source.Type == PlaylistFeed && source.Name != "" && !slices.Contains(names, source.Name) {
+			names = append(names, source.Name)
+		}
+	}
+	return names
+}
+
+// HasSource reports whether the video was fetched from the feed.
+func (v Video) HasSource(feedId string) bool {
+	return slices.ContainsFunc(v.Sources, func(source Source) bool {
+		return source.FeedId == feedId
+	})
+}
+
+// typeFromSources returns the type of the video. Only the Shorts feed of a
+// channel tells Shorts apart, so once stored as a Short a video stays one.
+func (v Video) typeFromSources() VideoType {
+	switch {
+	case slices.ContainsFunc(v.Sources, func(source Source) bool { return source.Type == ShortsFeed }):
+		return ShortVideo
+	case v.WasLive:
+		return LiveVideo
+	default:
+		return NormalVideo
+	}
+}
+
+// merge combines the videos fetched from several feeds into one, keeping the
+// order they were first seen in. The categories of all feeds apply.
+func (v Videos) merge() Videos {
+	merged := Videos{}
+	index := make(map[string]int)
+	for _, vid := range v {
+		i, ok := index[vid.VideoId]
+		if !ok {
+			index[vid.VideoId] = len(merged)
+			vid.Categories = slices.Clone(vid.Categories)
+			merged = append(merged, vid)
+			continue
+		}
+		for _, category := range vid.Categories {
+			if !slices.Contains(merged[i].Categories, category) {
+				merged[i].Categories = append(merged[i].Categories, category)
+			}
+		}
+		merged[i].Sources = append(slices.Clip(merged[i].Sources), vid.Sources...)
+		merged[i].Notify = merged[i].Notify || vid.Notify
+	}
+	return merged
+}
+
+// FeedsFromDB returns the feeds of the visible videos, sorted by name.
+func FeedsFromDB() ([]Source, error) {
+	ctx := context.Background()
+	db, err := database.Open()
+
+	if err != nil {
+		return nil, err
+	}
+
+	queries := database.New(db)
+
+	rows, err := queries.FetchFeeds(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var feeds []Source
+	for _, row := range rows {
+		// A channel is listed once for its uploads, live streams and Shorts.
+		if !slices.ContainsFunc(feeds, func(feed Source) bool { return feed.FeedId == row.FeedID }) {
+			feeds = append(feeds, Source{FeedId: row.FeedID, Name: row.FeedName})
+		}
+	}
+	return feeds, nil
+}
+
+// NameFeeds stores the names of the feeds, given by their ID, with all
+// videos fetched from them. That covers videos that weren't fetched again
+// since a feed was renamed and the playlists moved over without a name.
+func NameFeeds(names map[string]string) error {
+	ctx := context.Background()
+	db, err := database.Open()
+	if err != nil {
+		return err
+	}
+
+	queries := database.New(db)
+
+	for feedId, name := range names {
+		err = queries.RenameFeed(ctx, database.RenameFeedParams{FeedName: name, FeedID: feedId})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sourcesFromDB(ctx context.Context, queries *database.Queries, videoId string) []Source {
+	rows, _ := queries.FetchVideoSources(ctx, videoId)
+	sources := make([]Source, len(rows))
+	for i, row := range rows {
+		firstSeen, _ := time.Parse("2006-01-02 15:04:05", row.FirstSeen)
+		addedAt, _ := time.Parse("2006-01-02 15:04:05", row.AddedAt.String)
+		sources[i] = Source{
+			FeedId:    row.FeedID,
+			Type:      FeedType(row.FeedType),
+			Name:      row.FeedName,
+			FirstSeen: firstSeen,
+			AddedAt:   addedAt,
+		}
+	}
+	return sources
+}
+
+func writeSources(ctx context.Context, queries *database.Queries, vid Video) error {
+	for _, source := range vid.Sources {
+		addedAt := sql.NullString{}
+		if !source.AddedAt.IsZero() {
+			addedAt = sql.NullString{String: source.AddedAt.UTC().Format("2006-01-02 15:04:05"), Valid: true}
+		}
+		err := queries.AddVideoSource(ctx, database.AddVideoSourceParams{
+			VideoID:  vid.VideoId,
+			FeedID:   source.FeedId,
+			FeedType: string(source.Type),
+			FeedName: source.Name,
+			AddedAt:  addedAt,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package video
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	uploads := Source{FeedId: "UCa", Type: UploadsFeed, Name: "Channel"}
+	playlist := Source{FeedId: "PLb", Type: PlaylistFeed, Name: "Mix"}
+	vids := Videos{
+		{VideoId: "1", Categories: []string{"Music"}, Sources: []Source{uploads}},
+		{VideoId: "2", Sources: []Source{uploads}},
+		{VideoId: "1", Categories: []string{"Music", "Live"}, Sources: []Source{playlist}, Notify: true},
+	}
+
+	got := vids.merge()
+	want := Videos{
+		{VideoId: "1", Categories: []string{"Music", "Live"}, Sources: []Source{uploads, playlist}, Notify: true},
+		{VideoId: "2", Sources: []Source{uploads}},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %+v, want %+v", got, want)
+	}
+	if len(vids[0].Categories) != 1 {
+		t.Errorf("Merging changed the categories of the input to %v", vids[0].Categories)
+	}
+	if names := got[0].Playlists(); !reflect.DeepEqual(names, []string{"Mix"}) {
+		t.Errorf("Got playlists %v, want [Mix]", names)
+	}
+}
+
+func TestTypeFromSources(t *testing.T) {
+	testData := []struct {
+		name   string
+		vid    Video
+		output VideoType
+	}{
+		{"upload", Video{Sources: []Source{{Type: UploadsFeed}}}, NormalVideo},
+		{"live stream", Video{WasLive: true, Sources: []Source{{Type: LiveFeed}}}, LiveVideo},
+		{"premiere in a playlist", Video{WasLive: true, Sources: []Source{{Type: PlaylistFeed}}}, LiveVideo},
+		{"short", Video{Sources: []Source{{Type: PlaylistFeed}, {Type: ShortsFeed}}}, ShortVideo},
+		{"without sources", Video{}, NormalVideo},
+	}
+
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.vid.typeFromSources()
+
+			if got != tt.output {
+				t.Errorf("Want %s, got %s", tt.output, got)
+			}
+		})
+	}
+}
+
+func TestMergeCategories(t *testing.T) {
+	testData := []struct {
+		stored     string
+		categories []string
+		output     []string
+	}{
+		{"", nil, nil},
+		{"", []string{"Music"}, []string{"Music"}},
+		{"Tech,Music", []string{"Music", "Live"}, []string{"Tech", "Music", "Live"}},
+	}
+
+	for _, tt := range testData {
+		got := mergeCategories(tt.stored, tt.categories)
+
+		if !reflect.DeepEqual(got, tt.output) {
+			t.Errorf("Got %v for %q and %v, want %v", got, tt.stored, tt.categories, tt.output)
+		}
+	}
+}
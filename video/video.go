@@ -4,24 +4,21 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"io"
-	"net/http"
+	"slices"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/aaronzipp/deeptube/database"
-
-	_ "modernc.org/sqlite"
 )
 
+// VideoType tells normal videos, Shorts and past live streams apart.
 type VideoType string
 
-// Those types are not officially documented
-// see https://stackoverflow.com/a/76602819/8313407
 const (
-	NormalVideo VideoType = "UULF"
-	ShortVideo  VideoType = "UUSH"
-	LiveVideo   VideoType = "UULV"
+	NormalVideo VideoType = "normal"
+	ShortVideo  VideoType = "short"
+	LiveVideo   VideoType = "live"
 )
 
 const youtubeLinkTemplate = "https://www.youtube.com/watch_popup?v=%s"
@@ -30,49 +27,6 @@ const hoursInDay = 24
 const hoursInMonth = hoursInDay * 30
 const hoursInYear = hoursInDay * 365
 
-// DownloadThumbnail downloads a thumbnail if it doesn't exist in DB and saves it
-func DownloadThumbnail(videoID, thumbnailURL string) error {
-	if thumbnailURL == "" {
-		return nil
-	}
-
-	ctx := context.Background()
-	db, err := sql.Open("sqlite", "videos.db")
-	if err != nil {
-		return err
-	}
-	defer db.Close()
-
-	queries := database.New(db)
-
-	// Check if thumbnail already exists
-	_, err = queries.FetchThumbnail(ctx, videoID)
-	if err == nil {
-		return nil
-	}
-
-	resp, err := http.Get(thumbnailURL)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to download thumbnail: %s", resp.Status)
-	}
-
-	thumbnailData, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return err
-	}
-
-	err = queries.AddThumbnail(ctx, database.AddThumbnailParams{
-		VideoID:   videoID,
-		Thumbnail: thumbnailData,
-	})
-	return err
-}
-
 type Video struct {
 	Title        string
 	VideoId      string
@@ -83,15 +37,51 @@ type Video struct {
 	ThumbnailUrl string
 	Thumbnail    []byte
 	WasLive      bool
+	// Type is derived from the feeds and WasLive when the video is stored.
+	Type       VideoType
+	Categories []string
+	// Position is the second the video was last watched up to.
+	Position int
+	Watched  bool
+	Queued   bool
+	// FetchedAt is when the video was first stored in the database.
+	FetchedAt time.Time
+	// Notify is set for videos from subscriptions with notifications, it isn't stored.
+	Notify bool
+	// Sources are the feeds the video was fetched from.
+	Sources []Source
+	// AddedAt is when the video was added to the playlist it is listed for,
+	// only set by PlaylistVideosFromDB.
+	AddedAt time.Time
 }
 type Videos []Video
 
+// YouTubeLink returns a link that resumes the video at the stored position.
 func (v Video) YouTubeLink() string {
-	return fmt.Sprintf(youtubeLinkTemplate, v.VideoId)
+	return v.YouTubeLinkAt(v.Position)
+}
+
+// YouTubeLinkAt returns a link that starts playing the video at the given second.
+func (v Video) YouTubeLinkAt(seconds int) string {
+	link := fmt.Sprintf(youtubeLinkTemplate, v.VideoId)
+	if seconds > 0 {
+		link += fmt.Sprintf("&t=%ds", seconds)
+	}
+	return link
 }
 
 func (v Video) TimeSincePublished() string {
-	timeDifference := time.Since(v.PublishedAt)
+	return timeSince(v.PublishedAt)
+}
+
+// TimeSinceAdded is like TimeSincePublished for the time the video was added
+// to its playlist.
+func (v Video) TimeSinceAdded() string {
+	return timeSince(v.AddedAt)
+}
+
+func timeSince(t time.Time) string {
+	timeDifference := time.Since(t)
 
 	if timeDifference.Hours() >= hoursInYear {
 		years := int(timeDifference.Hours() / hoursInYear)
@@ -151,9 +141,61 @@ func (v Video) String() string {
 	)
 }
 
+func (v Video) Queue() error {
+	ctx := context.Background()
+	db, err := database.Open()
+
+	if err != nil {
+		return err
+	}
+
+	queries := database.New(db)
+
+	return queries.QueueVideo(ctx, v.VideoId)
+}
+
+func (v Video) Unqueue() error {
+	ctx := context.Background()
+	db, err := database.Open()
+
+	if err != nil {
+		return err
+	}
+
+	queries := database.New(db)
+
+	return queries.UnqueueVideo(ctx, v.VideoId)
+}
+
+func (v Video) MarkWatched() error {
+	ctx := context.Background()
+	db, err := database.Open()
+
+	if err != nil {
+		return err
+	}
+
+	queries := database.New(db)
+
+	return queries.MarkVideoWatched(ctx, v.VideoId)
+}
+
+func (v Video) MarkUnwatched() error {
+	ctx := context.Background()
+	db, err := database.Open()
+
+	if err != nil {
+		return err
+	}
+
+	queries := database.New(db)
+
+	return queries.MarkVideoUnwatched(ctx, v.VideoId)
+}
+
 func (v Video) Hide() error {
 	ctx := context.Background()
-	db, err := sql.Open("sqlite", "videos.db")
+	db, err := database.Open()
 
 	if err != nil {
 		return err
@@ -169,9 +211,34 @@ func (v Video) Hide() error {
 	return nil
 }
 
+// Progress returns the fraction of the video watched so far.
+func (v Video) Progress() float64 {
+	total := v.VideoLength.TotalSeconds()
+	if total == 0 {
+		return 0
+	}
+	return min(float64(v.Position)/float64(total), 1)
+}
+
+func (v Video) SetPosition(seconds int) error {
+	ctx := context.Background()
+	db, err := database.Open()
+
+	if err != nil {
+		return err
+	}
+
+	queries := database.New(db)
+
+	return queries.SetVideoPosition(ctx, database.SetVideoPositionParams{
+		Position: sql.NullInt64{Int64: int64(seconds), Valid: true},
+		VideoID:  v.VideoId,
+	})
+}
+
 func VideosFromDB(limit int) (Videos, error) {
 	ctx := context.Background()
-	db, err := sql.Open("sqlite", "videos.db")
+	db, err := database.Open()
 
 	if err != nil {
 		return nil, err
@@ -185,15 +252,95 @@ func VideosFromDB(limit int) (Videos, error) {
 		return nil, err
 	}
 
+	return fromDB(ctx, queries, dbVideos), nil
+}
+
+// QueuedVideosFromDB returns the queued videos, in the order they were queued.
+func QueuedVideosFromDB() (Videos, error) {
+	ctx := context.Background()
+	db, err := database.Open()
+
+	if err != nil {
+		return nil, err
+	}
+
+	queries := database.New(db)
+
+	dbVideos, err := queries.FetchQueuedVideos(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return fromDB(ctx, queries, dbVideos), nil
+}
+
+// PlaylistVideosFromDB returns the videos of the playlist, the ones added
+// last first.
+func PlaylistVideosFromDB(playlistId string) (Videos, error) {
+	ctx := context.Background()
+	db, err := database.Open()
+
+	if err != nil {
+		return nil, err
+	}
+
+	queries := database.New(db)
+
+	rows, err := queries.FetchPlaylistVideos(ctx, playlistId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	dbVideos := make([]database.Video, len(rows))
+	for i, row := range rows {
+		dbVideos[i] = row.Video
+	}
+	vids := fromDB(ctx, queries, dbVideos)
+	for i, row := range rows {
+		vids[i].AddedAt, _ = time.Parse("2006-01-02 15:04:05", row.AddedAt.String)
+	}
+	return vids, nil
+}
+
+// PlaylistItemsFromDB returns at most limit videos of the playlist, including
+// hidden ones, the ones added last first. Only VideoId and AddedAt are set.
+func PlaylistItemsFromDB(playlistId string, limit int) (Videos, error) {
+	ctx := context.Background()
+	db, err := database.Open()
+
+	if err != nil {
+		return nil, err
+	}
+
+	queries := database.New(db)
+
+	rows, err := queries.FetchPlaylistItems(ctx, database.FetchPlaylistItemsParams{
+		FeedID:   playlistId,
+		MaxItems: int64(limit),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	vids := make(Videos, len(rows))
+	for i, row := range rows {
+		addedAt, _ := time.Parse("2006-01-02 15:04:05", row.AddedAt.String)
+		vids[i] = Video{VideoId: row.VideoID, AddedAt: addedAt}
+	}
+	return vids, nil
+}
+
+func fromDB(ctx context.Context, queries *database.Queries, dbVideos []database.Video) Videos {
 	vids := make(Videos, len(dbVideos))
 	for i, vid := range dbVideos {
 		publishedTime, _ := time.Parse("2006-01-02 15:04:05", vid.PublishedAt.String)
+		fetchedTime, _ := time.Parse("2006-01-02 15:04:05", vid.FetchedAt.String)
 
-		thumbnailData, err := queries.FetchThumbnail(ctx, vid.VideoID)
-		if err != nil {
-			_ = DownloadThumbnail(vid.VideoID, vid.ThumbnailUrl.String)
-			thumbnailData, _ = queries.FetchThumbnail(ctx, vid.VideoID)
-		}
+		// Missing thumbnails are left empty, they are fetched in the background.
+		thumbnailData, _ := queries.FetchThumbnail(ctx, vid.VideoID)
 
 		vids[i] = Video{
 			Title:       vid.Title.String,
@@ -209,10 +356,24 @@ func VideosFromDB(limit int) (Videos, error) {
 			ThumbnailUrl: vid.ThumbnailUrl.String,
 			Thumbnail:    thumbnailData,
 			WasLive:      vid.WasLive.Int64 == 1,
+			Type:         VideoType(vid.VideoType),
+			Categories:   splitCategories(vid.Categories.String),
+			Position:     int(vid.Position.Int64),
+			Watched:      vid.WatchedAt.Valid,
+			Queued:       vid.QueuedAt.Valid,
+			FetchedAt:    fetchedTime,
+			Sources:      sourcesFromDB(ctx, queries, vid.VideoID),
 		}
 	}
 
-	return vids, nil
+	return vids
+}
+
+func splitCategories(categories string) []string {
+	if categories == "" {
+		return nil
+	}
+	return strings.Split(categories, ",")
 }
 
 func (v Videos) Sort() {
@@ -221,24 +382,56 @@ func (v Videos) Sort() {
 	})
 }
 
-func (v Videos) WriteToDB() error {
+// WriteToDB stores the videos with the feeds they were fetched from, skipping
+// archived ones, and returns the ones that weren't stored before. A video
+// fetched from several feeds is stored once with the categories of all. Listeners registered with OnAdded are notified
+// about them.
+func (v Videos) WriteToDB() (Videos, error) {
 	ctx := context.Background()
-	db, err := sql.Open("sqlite", "videos.db")
+	db, err := database.Open()
 
 	if err != nil {
+		return nil, err
 	}
 
 	queries := database.New(db)
 
-	for _, vid := range v {
-		err := queries.AddVideo(ctx, database.AddVideoParams{
+	var added Videos
+	for _, vid := range v.merge() {
+		if vid.Type == "" {
+			vid.Type = vid.typeFromSources()
+		}
+		archived, err := queries.IsVideoArchived(ctx, vid.VideoId)
+		if err != nil {
+			return nil, err
+		}
+		if archived == 1 {
+			continue
+		}
+		stored, err := queries.IsVideoStored(ctx, vid.VideoId)
+		if err != nil {
+			return nil, err
+		}
+		if stored == 0 {
+			added = append(added, vid)
+		} else {
+			// Feeds refreshed on their own mustn't drop the categories of
+			// the other feeds the video is in.
+			categories, err := queries.FetchVideoCategories(ctx, vid.VideoId)
+			if err != nil {
+				return nil, err
+			}
+			vid.Categories = mergeCategories(categories.String, vid.Categories)
+		}
+
+		err = queries.AddVideo(ctx, database.AddVideoParams{
 			VideoID:      vid.VideoId,
 			Title:        sql.NullString{String: vid.Title, Valid: true},
 			ThumbnailUrl: sql.NullString{String: vid.ThumbnailUrl, Valid: true},
 			ChannelName:  sql.NullString{String: vid.ChannelName, Valid: true},
 			Description:  sql.NullString{String: vid.Description, Valid: true},
 			PublishedAt: sql.NullString{
-				String: vid.PublishedAt.Format("2006-01-02 15:04:05"),
+				String: vid.PublishedAt.UTC().Format("2006-01-02 15:04:05"),
 				Valid:  true,
 			},
 			Hours:   sql.NullInt64{Int64: int64(vid.VideoLength.Hours), Valid: true},
@@ -250,11 +443,37 @@ func (v Videos) WriteToDB() error {
 				}
 				return 0
 			}(), Valid: true},
+			Categories: sql.NullString{String: strings.Join(vid.Categories, ","), Valid: true},
+			VideoType:  string(vid.Type),
 		})
 		if err != nil {
-			return err
+			return nil, err
+		}
+
+		err = writeSources(ctx, queries, vid)
+		if err != nil {
+			return nil, err
 		}
 	}
 
-	return nil
+	if len(added) > 0 {
+		notifyAdded(added)
+	}
+	return added, nil
+}
+
+// mergeCategories adds the categories to the stored ones, a comma separated list.
+func mergeCategories(stored string, categories []string) []string {
+	var merged []string
+	for _, category := range strings.Split(stored, ",") {
+		if category != "" {
+			merged = append(merged, category)
+		}
+	}
+	for _, category := range categories {
+		if !slices.Contains(merged, category) {
+			merged = append(merged, category)
+		}
+	}
+	return merged
 }
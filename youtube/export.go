@@ -0,0 +1,195 @@
+package youtube
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aaronzipp/deeptube/config"
+	"github.com/aaronzipp/deeptube/opml"
+)
+
+// Formats subscriptions and playlists can be exported to.
+const (
+	FormatOPML     = "opml"
+	FormatNewPipe  = "newpipe"
+	FormatFreeTube = "freetube"
+	FormatJSON     = "json"
+)
+
+var ExportFormats = []string{FormatOPML, FormatNewPipe, FormatFreeTube, FormatJSON}
+
+// jsonVersion is the version of the canonical JSON export.
+const jsonVersion = 1
+
+const (
+	channelURL  = "https://www.youtube.com/channel/"
+	playlistURL = "https://www.youtube.com/playlist?list="
+	feedURL     = "https://www.youtube.com/feeds/videos.xml"
+)
+
+// Export writes the subscriptions and playlists in the given format.
+// NewPipe and FreeTube only import channels, so they leave out the playlists.
+func Export(w io.Writer, format string, subs []config.Subscription, playlists []config.Playlist) error {
+	switch format {
+	case FormatOPML:
+		return ExportOPML(w, subs, playlists)
+	case FormatNewPipe:
+		return ExportNewPipe(w, subs)
+	case FormatFreeTube:
+		return ExportFreeTube(w, subs)
+	case FormatJSON:
+		return ExportJSON(w, subs, playlists)
+	default:
+		return fmt.Errorf("unknown export format %q, expected one of %s", format, strings.Join(ExportFormats, ", "))
+	}
+}
+
+// ExportOPML writes an outline with the feed of every channel and playlist.
+// Entries are grouped by their first category, all categories are kept as tags.
+func ExportOPML(w io.Writer, subs []config.Subscription, playlists []config.Playlist) error {
+	var outlines []opml.Outline
+	groups := make(map[string]int)
+	add := func(outline opml.Outline, categories []string) {
+		if len(categories) == 0 {
+			outlines = append(outlines, outline)
+			return
+		}
+		outline.Category = strings.Join(categories, ",")
+		i, ok := groups[categories[0]]
+		if !ok {
+			i = len(outlines)
+			groups[categories[0]] = i
+			outlines = append(outlines, opml.Outline{Text: categories[0]})
+		}
+		outlines[i].Outlines = append(outlines[i].Outlines, outline)
+	}
+
+	for _, sub := range subs {
+		add(opml.Outline{
+			Text:    sub.Channel,
+			Title:   sub.Channel,
+			Type:    "rss",
+			XMLURL:  feedURL + "?channel_id=" + sub.ID,
+			HTMLURL: channelURL + sub.ID,
+		}, sub.Categories)
+	}
+	for _, playlist := range playlists {
+		add(opml.Outline{
+			Text:    playlist.Playlist,
+			Title:   playlist.Playlist,
+			Type:    "rss",
+			XMLURL:  feedURL + "?playlist_id=" + playlist.ID,
+			HTMLURL: playlistURL + playlist.ID,
+		}, playlist.Categories)
+	}
+
+	doc := opml.Document{
+		Head: opml.Head{Title: "DeepTube subscriptions"},
+		Body: opml.Body{Outlines: outlines},
+	}
+	return doc.Write(w)
+}
+
+// NewPipe writes its version into subscription exports but reads only the
+// subscriptions when importing. The export gives the release whose format it
+// follows, 0.27.6 with its version code, which every later NewPipe imports.
+const (
+	newPipeFormatVersion     = "0.27.6"
+	newPipeFormatVersionCode = 1005
+)
+
+type newPipeExport struct {
+	AppVersion    string                `json:"app_version"`
+	AppVersionInt int                   `json:"app_version_int"`
+	Subscriptions []newPipeSubscription `json:"subscriptions"`
+}
+
+type newPipeSubscription struct {
+	ServiceID int    `json:"service_id"`
+	URL       string `json:"url"`
+	Name      string `json:"name"`
+}
+
+// ExportNewPipe writes the channels in the format of NewPipe's subscription export.
+func ExportNewPipe(w io.Writer, subs []config.Subscription) error {
+	export := newPipeExport{
+		AppVersion:    newPipeFormatVersion,
+		AppVersionInt: newPipeFormatVersionCode,
+		Subscriptions: make([]newPipeSubscription, len(subs)),
+	}
+	for i, sub := range subs {
+		// Service 0 is YouTube.
+		export.Subscriptions[i] = newPipeSubscription{URL: channelURL + sub.ID, Name: sub.Channel}
+	}
+	return writeJSON(w, export)
+}
+
+type freeTubeProfile struct {
+	ID            string                 `json:"_id"`
+	Name          string                 `json:"name"`
+	BgColor       string                 `json:"bgColor"`
+	TextColor     string                 `json:"textColor"`
+	Subscriptions []freeTubeSubscription `json:"subscriptions"`
+}
+
+type freeTubeSubscription struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Thumbnail string `json:"thumbnail"`
+}
+
+// ExportFreeTube writes the channels as FreeTube profiles, one JSON object per
+// line like FreeTube's profiles.db. Every category becomes a profile of its own
+// next to the profile with all channels.
+func ExportFreeTube(w io.Writer, subs []config.Subscription) error {
+	profiles := []freeTubeProfile{{ID: "allChannels", Name: "All Channels"}}
+	categories := make(map[string]int)
+	for _, sub := range subs {
+		subscription := freeTubeSubscription{ID: sub.ID, Name: sub.Channel}
+		profiles[0].Subscriptions = append(profiles[0].Subscriptions, subscription)
+
+		for _, category := range sub.Categories {
+			i, ok := categories[category]
+			if !ok {
+				i = len(profiles)
+				categories[category] = i
+				profiles = append(profiles, freeTubeProfile{ID: category, Name: category})
+			}
+			profiles[i].Subscriptions = append(profiles[i].Subscriptions, subscription)
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	for _, profile := range profiles {
+		profile.BgColor = "#000000"
+		profile.TextColor = "#FFFFFF"
+		err := encoder.Encode(profile)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type jsonExport struct {
+	Version       int                   `json:"version"`
+	Subscriptions []config.Subscription `json:"subscriptions"`
+	Playlists     []config.Playlist     `json:"playlists"`
+}
+
+// ExportJSON writes the subscriptions and playlists with all their settings.
+func ExportJSON(w io.Writer, subs []config.Subscription, playlists []config.Playlist) error {
+	return writeJSON(w, jsonExport{
+		Version:       jsonVersion,
+		Subscriptions: subs,
+		Playlists:     playlists,
+	})
+}
+
+func writeJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
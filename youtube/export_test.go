@@ -0,0 +1,77 @@
+package youtube
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aaronzipp/deeptube/config"
+)
+
+var exportSubs = []config.Subscription{
+	{
+		Channel: "Compilers",
+		Feed:    config.Feed{ID: "UC0000000000000000000001", Categories: []string{"Tech", "Programming"}},
+	},
+	{Channel: "Cooking", Feed: config.Feed{ID: "UC0000000000000000000002"}},
+}
+
+func TestExportOPML(t *testing.T) {
+	var buf bytes.Buffer
+	playlists := []config.Playlist{{Playlist: "Mix", Feed: config.Feed{ID: "PL1", Categories: []string{"Music"}}}}
+	err := ExportOPML(&buf, exportSubs, playlists)
+	if err != nil {
+		t.Fatalf("Got an unexpected error: %q", err)
+	}
+	if !strings.Contains(buf.String(), "playlist_id=PL1") {
+		t.Errorf("Got\n%s\nwant the playlist feed", buf.String())
+	}
+
+	got, err := ParseOPML(&buf)
+	if err != nil {
+		t.Fatalf("Got an unexpected error: %q", err)
+	}
+	if !reflect.DeepEqual(got, exportSubs) {
+		t.Errorf("Got %+v, want %+v", got, exportSubs)
+	}
+}
+
+func TestExportNewPipe(t *testing.T) {
+	var buf bytes.Buffer
+	err := ExportNewPipe(&buf, exportSubs[1:])
+	if err != nil {
+		t.Fatalf("Got an unexpected error: %q", err)
+	}
+
+	want := `{"app_version":"0.27.6","app_version_int":1005,"subscriptions":[` +
+		`{"service_id":0,"url":"https://www.youtube.com/channel/UC0000000000000000000002","name":"Cooking"}]}`
+	var got bytes.Buffer
+	err = json.Compact(&got, buf.Bytes())
+	if err != nil {
+		t.Fatalf("Got an unexpected error: %q", err)
+	}
+	if got.String() != want {
+		t.Errorf("Got %s, want %s", got.String(), want)
+	}
+}
+
+func TestExportFreeTube(t *testing.T) {
+	var buf bytes.Buffer
+	err := ExportFreeTube(&buf, exportSubs)
+	if err != nil {
+		t.Fatalf("Got an unexpected error: %q", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	want := []string{`"_id":"allChannels"`, `"_id":"Tech"`, `"_id":"Programming"`}
+	if len(lines) != len(want) {
+		t.Fatalf("Got %d profiles, want %d:\n%s", len(lines), len(want), buf.String())
+	}
+	for i, line := range lines {
+		if !strings.Contains(line, want[i]) {
+			t.Errorf("Got profile %d %s, want %s", i, line, want[i])
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package youtube
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/aaronzipp/deeptube/config"
+	"github.com/aaronzipp/deeptube/opml"
+)
+
+// channelIDPattern matches the channel ID in channel and feed URLs.
+var channelIDPattern = regexp.MustCompile(`UC[\w-]{22}`)
+
+// ParseTakeout reads the subscriptions.csv of a Google Takeout export, with
+// the columns channel ID, channel URL and channel title.
+func ParseTakeout(r io.Reader) ([]config.Subscription, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	var subs []config.Subscription
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		// Skips the header and empty lines at the end.
+		if len(record) < 3 || !channelIDPattern.MatchString(record[0]) {
+			continue
+		}
+		subs = append(subs, config.Subscription{Channel: record[2], Feed: config.Feed{ID: record[0]}})
+	}
+	return subs, nil
+}
+
+// ParseOPML reads the YouTube channels of an OPML file, the groups they are
+// in and their tags become categories. Feeds from other sites are skipped.
+func ParseOPML(r io.Reader) ([]config.Subscription, error) {
+	doc, err := opml.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var subs []config.Subscription
+	for _, feed := range doc.Feeds() {
+		id := channelIDPattern.FindString(feed.XMLURL)
+		if id == "" {
+			id = channelIDPattern.FindString(feed.HTMLURL)
+		}
+		if id == "" {
+			continue
+		}
+		name := feed.Title
+		if name == "" {
+			name = feed.Text
+		}
+		subs = append(subs, config.Subscription{Channel: name, Feed: config.Feed{ID: id, Categories: feed.Groups}})
+	}
+	return subs, nil
+}
+
+// ImportSubscriptions reads the subscriptions from a Takeout CSV or an OPML
+// file and appends the ones missing to the subscriptions of cfg. It returns
+// the subscriptions that were added.
+func ImportSubscriptions(from string, cfg config.Config) ([]config.Subscription, error) {
+	file, err := os.Open(from)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var subs []config.Subscription
+	switch strings.ToLower(filepath.Ext(from)) {
+	case ".csv":
+		subs, err = ParseTakeout(file)
+	case ".opml", ".xml":
+		subs, err = ParseOPML(file)
+	default:
+		return nil, fmt.Errorf("unknown subscriptions format %q, expected .csv or .opml", filepath.Ext(from))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed reading %s: %w", from, err)
+	}
+
+	return config.AppendSubscriptions(cfg, subs)
+}
@@ -0,0 +1,50 @@
+package youtube
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aaronzipp/deeptube/config"
+)
+
+func TestParseTakeout(t *testing.T) {
+	export := `Channel Id,Channel Url,Channel Title
+UC0000000000000000000001,http://www.youtube.com/channel/UC0000000000000000000001,Compilers
+UC0000000000000000000002,http://www.youtube.com/channel/UC0000000000000000000002,"Cooking, Baking"
+
+`
+	got, err := ParseTakeout(strings.NewReader(export))
+	if err != nil {
+		t.Fatalf("Got an unexpected error: %q", err)
+	}
+	want := []config.Subscription{
+		{Channel: "Compilers", Feed: config.Feed{ID: "UC0000000000000000000001"}},
+		{Channel: "Cooking, Baking", Feed: config.Feed{ID: "UC0000000000000000000002"}},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %+v, want %+v", got, want)
+	}
+}
+
+func TestParseOPML(t *testing.T) {
+	export := `<opml version="1.1"><body>
+<outline text="Tech">
+  <outline text="Compilers" xmlUrl="https://www.youtube.com/feeds/videos.xml?channel_id=UC0000000000000000000001"/>
+</outline>
+<outline text="Blog" xmlUrl="https://example.com/feed.xml"/>
+</body></opml>`
+
+	got, err := ParseOPML(strings.NewReader(export))
+	if err != nil {
+		t.Fatalf("Got an unexpected error: %q", err)
+	}
+	want := []config.Subscription{
+		{Channel: "Compilers", Feed: config.Feed{ID: "UC0000000000000000000001", Categories: []string{"Tech"}}},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %+v, want %+v", got, want)
+	}
+}
@@ -2,83 +2,154 @@ package youtube
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"os"
+	"reflect"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/aaronzipp/deeptube/config"
 	"github.com/aaronzipp/deeptube/video"
 
-	"github.com/joho/godotenv"
 	"google.golang.org/api/option"
 	"google.golang.org/api/youtube/v3"
-	"gopkg.in/yaml.v3"
 )
 
-type Subscription struct {
-	Channel         string   `yaml:"channel"`
-	ID              string   `yaml:"id"`
-	Categories      []string `yaml:"categories"`
-	Live            bool     `yaml:"live,omitempty"`
-	ExcludeKeywords []string `yaml:"exclude_keywords,omitempty"`
-	Shorts          bool     `yaml:"shorts,omitempty"`
-}
-
-type Playlist struct {
-	Playlist   string   `yaml:"playlist"`
-	ID         string   `yaml:"id"`
-	Categories []string `yaml:"categories"`
-}
-
-func YoutubeService() (*youtube.Service, error) {
-	ctx := context.Background()
-	err := godotenv.Load()
-	if err != nil {
-		return nil, err
+func YoutubeService(apiKey string) (*youtube.Service, error) {
+	if apiKey == "" {
+		return nil, errors.New("no YouTube API key, set api.key in " + config.DefaultPath)
 	}
+	ctx := context.Background()
 
 	return youtube.NewService(
 		ctx,
-		option.WithAPIKey(os.Getenv("YOUTUBE_API_KEY")),
+		option.WithAPIKey(apiKey),
 	)
 }
 
-func FetchVideoIdsFromPlaylist(playlistId string) ([]string, error) {
-	youtubeService, err := YoutubeService()
-	if err != nil {
-		return nil, err
-	}
-	call := youtubeService.PlaylistItems.List([]string{"contentDetails"})
-	result, err := call.PlaylistId(playlistId).MaxResults(10).Do()
+// maxResults is the largest page the API returns, of playlist items as well
+// as of videos.
+const maxResults = 50
 
-	if err != nil {
-		return nil, err
-	}
+// errEnoughItems stops paging through a playlist.
+var errEnoughItems = errors.New("enough playlist items")
 
-	output := result.Items
+// PlaylistOptions select which videos of a playlist are fetched.
+type PlaylistOptions struct {
+	MaxItems int
+	// Reverse takes the videos from the end of the playlist, newest first.
+	Reverse bool
+	// TrackAdded takes the videos added last, wherever they are in the playlist.
+	TrackAdded bool
+}
 
-	ids := make([]string, len(output))
-	for i, item := range output {
-		ids[i] = item.ContentDetails.VideoId
-	}
+// PlaylistItem is a video in a playlist.
+type PlaylistItem struct {
+	VideoId string
+	// AddedAt is when the video was added to the playlist.
+	AddedAt time.Time
+}
 
-	return ids, nil
+// pagedPlaylist is a playlist that was paged through completely for a feed.
+type pagedPlaylist struct {
+	playlistId string
+	etag       string
+	feed       config.Feed
+	options    PlaylistOptions
 }
 
-func FetchVideos(ids []string) (video.Videos, error) {
-	youtubeService, err := YoutubeService()
+// pagedPlaylists holds the playlists paged through completely by their ID.
+// They aren't paged through again as long as neither they nor their feed
+// change, their videos are taken from the database instead.
+var (
+	pagedMu        sync.Mutex
+	pagedPlaylists = make(map[string]pagedPlaylist)
+)
+
+// unchanged reports whether the playlist was paged through with the same
+// etag, feed and options before.
+func (p pagedPlaylist) unchanged() bool {
+	pagedMu.Lock()
+	defer pagedMu.Unlock()
+	before, ok := pagedPlaylists[p.playlistId]
+	return ok && before.etag == p.etag && before.options == p.options && reflect.DeepEqual(before.feed, p.feed)
+}
+
+// remember is called once the videos of the playlist were stored.
+func (p pagedPlaylist) remember() {
+	pagedMu.Lock()
+	defer pagedMu.Unlock()
+	pagedPlaylists[p.playlistId] = p
+}
+
+// playlistETag returns the etag of the playlist, which changes with its videos.
+func playlistETag(youtubeService *youtube.Service, playlistId string) (string, error) {
+	result, err := youtubeService.Playlists.List([]string{"contentDetails"}).Id(playlistId).Do()
 	if err != nil {
-		return nil, err
+		return "", err
+	}
+	if len(result.Items) == 0 {
+		return "", nil
 	}
+	return result.Items[0].Etag, nil
+}
 
-	result, err := youtubeService.Videos.List(
-		[]string{"contentDetails", "snippet"},
-	).Id(ids...).Do()
-	if err != nil {
+// FetchPlaylistItems returns the videos of the playlist picked by options.
+// Reverse and TrackAdded page through the whole playlist, which costs a
+// request per 50 videos.
+func FetchPlaylistItems(
+	youtubeService *youtube.Service,
+	playlistId string,
+	options PlaylistOptions,
+) ([]PlaylistItem, error) {
+	pageSize := min(options.MaxItems, maxResults)
+	if options.Reverse || options.TrackAdded {
+		pageSize = maxResults
+	}
+	// The snippet holds the time the video was added to the playlist.
+	call := youtubeService.PlaylistItems.List([]string{"contentDetails", "snippet"}).
+		PlaylistId(playlistId).
+		MaxResults(int64(pageSize))
+
+	var items []PlaylistItem
+	err := call.Pages(context.Background(), func(page *youtube.PlaylistItemListResponse) error {
+		for _, item := range page.Items {
+			addedAt, _ := time.Parse(time.RFC3339, item.Snippet.PublishedAt)
+			items = append(items, PlaylistItem{VideoId: item.ContentDetails.VideoId, AddedAt: addedAt})
+		}
+		if !options.Reverse && !options.TrackAdded && len(items) >= options.MaxItems {
+			return errEnoughItems
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errEnoughItems) {
 		return nil, err
 	}
 
-	output := result.Items
+	switch {
+	case options.TrackAdded:
+		slices.SortStableFunc(items, func(a, b PlaylistItem) int {
+			return b.AddedAt.Compare(a.AddedAt)
+		})
+	case options.Reverse:
+		slices.Reverse(items)
+	}
+	return items[:min(len(items), options.MaxItems)], nil
+}
+
+func FetchVideos(youtubeService *youtube.Service, ids []string) (video.Videos, error) {
+	var output []*youtube.Video
+	for batch := range slices.Chunk(ids, maxResults) {
+		result, err := youtubeService.Videos.List(
+			[]string{"contentDetails", "snippet", "liveStreamingDetails"},
+		).Id(batch...).Do()
+		if err != nil {
+			return nil, err
+		}
+		output = append(output, result.Items...)
+	}
 
 	videos := make(video.Videos, len(output))
 
@@ -110,10 +181,8 @@ func FetchVideos(ids []string) (video.Videos, error) {
 			Description:  item.Snippet.Description,
 			PublishedAt:  publishedAt,
 			VideoLength:  length,
-		}
-
-		if thumbnail != "" {
-			_ = video.DownloadThumbnail(item.Id, thumbnail)
+			// Premieres have streaming details as well.
+			WasLive: item.LiveStreamingDetails != nil,
 		}
 	}
 
@@ -134,111 +203,184 @@ func shouldExcludeVideo(title string, excludeKeywords []string) bool {
 	return false
 }
 
-func FetchAllVideos(subscriptions []Subscription, playlists []Playlist) (video.Videos, error) {
-	playlistExcludes := make(map[string][]string)
+// source is a playlist videos are fetched from, together with the feed it
+// belongs to. A subscription has a source for each of its upload playlists.
+type source struct {
+	playlistId string
+	feed       config.Feed
+	feedType   video.FeedType
+	// name is the channel or playlist name from the config.
+	name    string
+	options PlaylistOptions
+}
+
+// channelFeeds are the feed types of a channel with the prefix of their
+// playlist. The prefixes are not officially documented,
+// see https://stackoverflow.com/a/76602819/8313407
+var channelFeeds = map[video.FeedType]string{
+	video.UploadsFeed: "UULF",
+	video.LiveFeed:    "UULV",
+	video.ShortsFeed:  "UUSH",
+}
 
-	playlistIds := []string{}
+func sources(subscriptions []config.Subscription, playlists []config.Playlist) []source {
+	var result []source
 	for _, subscription := range subscriptions {
-		playlistId := strings.Replace(subscription.ID, "UC", string(video.NormalVideo), 1)
-		playlistIds = append(playlistIds, playlistId)
-		playlistExcludes[playlistId] = subscription.ExcludeKeywords
-
+		types := []video.FeedType{video.UploadsFeed}
 		if subscription.Live {
-			playlistId = strings.Replace(subscription.ID, "UC", string(video.LiveVideo), 1)
-			playlistIds = append(playlistIds, playlistId)
-			playlistExcludes[playlistId] = subscription.ExcludeKeywords
+			types = append(types, video.LiveFeed)
 		}
 		if subscription.Shorts {
-			playlistId = strings.Replace(subscription.ID, "UC", string(video.ShortVideo), 1)
-			playlistIds = append(playlistIds, playlistId)
-			playlistExcludes[playlistId] = subscription.ExcludeKeywords
+			types = append(types, video.ShortsFeed)
+		}
+		for _, feedType := range types {
+			result = append(result, source{
+				playlistId: strings.Replace(subscription.ID, "UC", channelFeeds[feedType], 1),
+				feed:       subscription.Feed,
+				feedType:   feedType,
+				name:       subscription.Channel,
+				options:    PlaylistOptions{MaxItems: subscription.Items()},
+			})
 		}
 	}
 	for _, playlist := range playlists {
-		playlistIds = append(playlistIds, playlist.ID)
-		// Playlists don't have exclude_keywords, so they get an empty array
-		playlistExcludes[playlist.ID] = []string{}
+		result = append(result, source{
+			playlistId: playlist.ID,
+			feed:       playlist.Feed,
+			feedType:   video.PlaylistFeed,
+			name:       playlist.Playlist,
+			options: PlaylistOptions{
+				MaxItems:   playlist.Items(),
+				Reverse:    playlist.Reverse,
+				TrackAdded: playlist.TrackAdded,
+			},
+		})
 	}
+	return result
+}
 
+// keeps reports whether vid passes the filters of the feed. The upload
+// playlists of channels only hold live streams when asked for.
+func (s source) keeps(vid video.Video) bool {
+	if vid.WasLive && s.feedType == video.PlaylistFeed && !s.feed.Live {
+		return false
+	}
+	return !shouldExcludeVideo(vid.Title, s.feed.ExcludeKeywords)
+}
+
+// fetchItems returns the videos of the source's playlist. A playlist paged
+// through completely that didn't change since, for the same feed, costs a
+// single request, its videos are the ones stored the last time. Otherwise
+// paged is set for remembering the playlist once its videos are stored.
+func (s source) fetchItems(youtubeService *youtube.Service) (items []PlaylistItem, paged *pagedPlaylist, err error) {
+	if s.options.Reverse || s.options.TrackAdded {
+		etag, err := playlistETag(youtubeService, s.playlistId)
+		if err != nil {
+			return nil, nil, err
+		}
+		paged = &pagedPlaylist{playlistId: s.playlistId, etag: etag, feed: s.feed, options: s.options}
+		if etag == "" {
+			paged = nil
+		} else if paged.unchanged() {
+			stored, err := video.PlaylistItemsFromDB(s.playlistId, s.options.MaxItems)
+			if err != nil {
+				return nil, nil, err
+			}
+			items = make([]PlaylistItem, len(stored))
+			for i, vid := range stored {
+				items[i] = PlaylistItem{VideoId: vid.VideoId, AddedAt: vid.AddedAt}
+			}
+			return items, nil, nil
+		}
+	}
+	items, err = FetchPlaylistItems(youtubeService, s.playlistId, s.options)
+	return items, paged, err
+}
+
+// fetchAllVideos returns the videos of the feeds and the playlists to
+// remember once they are stored.
+func fetchAllVideos(
+	youtubeService *youtube.Service,
+	subscriptions []config.Subscription,
+	playlists []config.Playlist,
+) (video.Videos, []pagedPlaylist, error) {
 	vids := video.Videos{}
-	for _, playlistId := range playlistIds {
-		videoIds, err := FetchVideoIdsFromPlaylist(playlistId)
+	var pagedAll []pagedPlaylist
+	for _, src := range sources(subscriptions, playlists) {
+		items, paged, err := src.fetchItems(youtubeService)
 		if err != nil {
-			return nil, fmt.Errorf(
+			return nil, nil, fmt.Errorf(
 				"failed fetching video ids from playlist %q: %!s",
-				playlistId,
+				src.playlistId,
 				err,
 			)
 		}
-		playlistVids, err := FetchVideos(videoIds)
-		if len(playlistVids) == 0 {
-			continue
+		if paged != nil {
+			pagedAll = append(pagedAll, *paged)
 		}
+		videoIds := make([]string, len(items))
+		addedAt := make(map[string]time.Time)
+		for i, item := range items {
+			videoIds[i] = item.VideoId
+			addedAt[item.VideoId] = item.AddedAt
+		}
+		playlistVids, err := FetchVideos(youtubeService, videoIds)
 		if err != nil {
-			return nil, fmt.Errorf("failed fetching videos with ids %+v: %!s", videoIds, err)
+			return nil, nil, fmt.Errorf("failed fetching videos with ids %+v: %!s", videoIds, err)
+		}
+		if len(playlistVids) == 0 {
+			continue
 		}
 
-		excludes := playlistExcludes[playlistId]
 		filteredVids := make(video.Videos, 0, len(playlistVids))
 		for _, vid := range playlistVids {
-			if !shouldExcludeVideo(vid.Title, excludes) {
+			if src.keeps(vid) {
+				vid.Categories = src.feed.Categories
+				vid.Notify = src.feed.Notify
+				vid.Sources = []video.Source{{FeedId: src.feed.ID, Type: src.feedType, Name: src.name}}
+				if src.feedType == video.PlaylistFeed {
+					vid.Sources[0].AddedAt = addedAt[vid.VideoId]
+				}
 				filteredVids = append(filteredVids, vid)
 			}
 		}
 
 		vids = append(vids, filteredVids...)
 	}
-	return vids, nil
+	return vids, pagedAll, nil
 }
 
-func ParseSubscriptions(filename string) ([]Subscription, error) {
-	data, err := os.ReadFile(filename)
-	if err != nil {
-		return nil, err
-	}
-
-	var subs []Subscription
-	err = yaml.Unmarshal(data, &subs)
-	if err != nil {
-		return nil, err
-	}
-
-	return subs, nil
+// RefreshVideos fetches and stores the latest videos of the configured feeds
+// and returns the ones that weren't known before.
+func RefreshVideos(cfg config.Config) (video.Videos, error) {
+	return RefreshFeeds(cfg.API.Key, cfg.Subscriptions, cfg.Playlists)
 }
 
-func ParsePlaylists(filename string) ([]Playlist, error) {
-	data, err := os.ReadFile(filename)
+// RefreshFeeds is RefreshVideos for only some of the feeds, e.g. the ones
+// just added to the config.
+func RefreshFeeds(apiKey string, subscriptions []config.Subscription, playlists []config.Playlist) (video.Videos, error) {
+	youtubeService, err := YoutubeService(apiKey)
 	if err != nil {
 		return nil, err
 	}
+	videos, paged, err := fetchAllVideos(youtubeService, subscriptions, playlists)
 
-	var playlists []Playlist
-	err = yaml.Unmarshal(data, &playlists)
 	if err != nil {
 		return nil, err
 	}
 
-	return playlists, nil
-}
-
-func RefreshVideos() error {
-
-	subscriptions, err := ParseSubscriptions("subscriptions.yaml")
+	videos.Sort()
+	added, err := videos.WriteToDB()
 	if err != nil {
-		return err
+		return nil, err
 	}
-	playlists, err := ParsePlaylists("playlists.yaml")
-	if err != nil {
-		return err
+	for _, playlist := range paged {
+		playlist.remember()
 	}
-	videos, err := FetchAllVideos(subscriptions, playlists)
 
-	if err != nil {
-		return err
+	names := make(map[string]string)
+	for _, src := range sources(subscriptions, playlists) {
+		names[src.feed.ID] = src.name
 	}
-
-	videos.Sort()
-	videos.WriteToDB()
-
-	return nil
+	return added, video.NameFeeds(names)
 }
@@ -0,0 +1,99 @@
+package youtube
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aaronzipp/deeptube/config"
+	"github.com/aaronzipp/deeptube/video"
+)
+
+func TestSources(t *testing.T) {
+	subs := []config.Subscription{
+		{Channel: "A", Feed: config.Feed{ID: "UCa", Live: true, MaxItems: 5}, Shorts: true},
+	}
+	playlists := []config.Playlist{
+		{Playlist: "B", Feed: config.Feed{ID: "PLb"}, Reverse: true},
+	}
+
+	got := sources(subs, playlists)
+	want := []source{
+		{playlistId: "UULFa", feed: subs[0].Feed, feedType: video.UploadsFeed, name: "A", options: PlaylistOptions{MaxItems: 5}},
+		{playlistId: "UULVa", feed: subs[0].Feed, feedType: video.LiveFeed, name: "A", options: PlaylistOptions{MaxItems: 5}},
+		{playlistId: "UUSHa", feed: subs[0].Feed, feedType: video.ShortsFeed, name: "A", options: PlaylistOptions{MaxItems: 5}},
+		{
+			playlistId: "PLb",
+			feed:       playlists[0].Feed,
+			feedType:   video.PlaylistFeed,
+			name:       "B",
+			options:    PlaylistOptions{MaxItems: config.DefaultMaxItems, Reverse: true},
+		},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %+v, want %+v", got, want)
+	}
+}
+
+func TestKeeps(t *testing.T) {
+	testData := []struct {
+		src    source
+		vid    video.Video
+		output bool
+	}{
+		{src: source{}, vid: video.Video{Title: "Stream", WasLive: true}, output: true},
+		{src: source{feedType: video.PlaylistFeed}, vid: video.Video{Title: "Stream", WasLive: true}, output: false},
+		{
+			src:    source{feed: config.Feed{ExcludeKeywords: []string{"trailer"}}},
+			vid:    video.Video{Title: "Official Trailer"},
+			output: false,
+		},
+		{src: source{feedType: video.PlaylistFeed}, vid: video.Video{Title: "Upload"}, output: true},
+	}
+
+	for _, tt := range testData {
+		got := tt.src.keeps(tt.vid)
+
+		if got != tt.output {
+			t.Errorf("Got %t for %+v in %+v, want %t", got, tt.vid, tt.src, tt.output)
+		}
+	}
+}
+
+func TestUnchanged(t *testing.T) {
+	paged := pagedPlaylist{
+		playlistId: "PLtest",
+		etag:       "a",
+		feed:       config.Feed{ID: "PLtest", Categories: []string{"Music"}},
+		options:    PlaylistOptions{MaxItems: 10, Reverse: true},
+	}
+	paged.remember()
+
+	changed := func(change func(p *pagedPlaylist)) pagedPlaylist {
+		p := paged
+		change(&p)
+		return p
+	}
+	testData := []struct {
+		name   string
+		paged  pagedPlaylist
+		output bool
+	}{
+		{"same", paged, true},
+		{"changed", changed(func(p *pagedPlaylist) { p.etag = "b" }), false},
+		{"more items", changed(func(p *pagedPlaylist) { p.options.MaxItems = 20 }), false},
+		{"live", changed(func(p *pagedPlaylist) { p.feed.Live = true }), false},
+		{"keywords", changed(func(p *pagedPlaylist) { p.feed.ExcludeKeywords = []string{"trailer"} }), false},
+		{"other playlist", changed(func(p *pagedPlaylist) { p.playlistId = "PLother" }), false},
+	}
+
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.paged.unchanged()
+
+			if got != tt.output {
+				t.Errorf("Got %t, want %t", got, tt.output)
+			}
+		})
+	}
+}